@@ -0,0 +1,214 @@
+package text_encoding
+
+import (
+	"encoding/base32"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// ErrInvalidBase32 is returned when base32 input cannot be decoded.
+const ErrInvalidBase32 = "failed to decode base32"
+
+// Base32 variants for Base32Options.Variant.
+const (
+	// Base32Standard is the RFC 4648 alphabet A-Z and 2-7. It is the default.
+	Base32Standard = "standard"
+	// Base32Hex is the RFC 4648 extended hex alphabet 0-9 and A-V.
+	Base32Hex = "hex"
+	// Base32Crockford is Douglas Crockford's alphabet, which omits I, L, O and U.
+	Base32Crockford = "crockford"
+	// Base32Z is the human-oriented z-base-32 alphabet.
+	Base32Z = "zbase32"
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// crockfordCheckSymbols maps a value modulo 37 to its check symbol.
+	crockfordCheckSymbols = crockfordAlphabet + "*~$=U"
+)
+
+var (
+	crockfordEncoding = base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding)
+	zBase32Encoding   = base32.NewEncoding("ybndrfg8ejkmcpqxot1uwisza345h769").WithPadding(base32.NoPadding)
+)
+
+// Base32Options configures the base32 encoders and decoders.
+// Padding applies to the standard and hex variants; Crockford and z-base-32 are never
+// padded. Checksum appends a Crockford check symbol when encoding and verifies it when
+// decoding. Lenient ignores spaces and tabs in encoded input; line breaks are always
+// ignored. Decoding is case-insensitive, and Crockford decoding also ignores hyphens
+// and reads I and L as 1 and O as 0.
+type Base32Options struct {
+	Variant  string `js:"variant"`
+	Padding  string `js:"padding"`
+	Checksum bool   `js:"checksum"`
+	Lenient  bool   `js:"lenient"`
+}
+
+// encoding resolves the variant and padding options.
+func (o Base32Options) encoding() (variant string, enc *base32.Encoding, padding string, err error) {
+	variant = strings.ToLower(o.Variant)
+	if o.Checksum && variant != Base32Crockford {
+		return "", nil, "", fmt.Errorf("%s: checksum requires the %q variant", ErrInvalidOption, Base32Crockford)
+	}
+	switch variant {
+	case "", Base32Standard:
+		variant, enc = Base32Standard, base32.StdEncoding
+	case Base32Hex:
+		enc = base32.HexEncoding
+	case Base32Crockford, Base32Z:
+		if o.Padding != "" && !strings.EqualFold(o.Padding, PaddingNone) {
+			return "", nil, "", fmt.Errorf("%s: %s base32 is never padded", ErrInvalidOption, variant)
+		}
+		enc = zBase32Encoding
+		if variant == Base32Crockford {
+			enc = crockfordEncoding
+		}
+		return variant, enc, PaddingNone, nil
+	default:
+		return "", nil, "", fmt.Errorf("%s: variant must be one of %q, %q, %q or %q, got %q",
+			ErrInvalidOption, Base32Standard, Base32Hex, Base32Crockford, Base32Z, o.Variant)
+	}
+	padding, err = paddingMode(o.Padding)
+	if err != nil {
+		return "", nil, "", err
+	}
+	return variant, enc, padding, nil
+}
+
+// encode converts data to base32 as configured.
+func (o Base32Options) encode(data []byte) (string, error) {
+	_, enc, padding, err := o.encoding()
+	if err != nil {
+		return "", err
+	}
+	if padding == PaddingNone {
+		enc = enc.WithPadding(base32.NoPadding)
+	}
+	encoded := enc.EncodeToString(data)
+	if o.Checksum {
+		encoded += string(crockfordCheckSymbols[crockfordChecksum(encoded)])
+	}
+	return encoded, nil
+}
+
+// decode converts base32 to bytes as configured.
+func (o Base32Options) decode(encoded string) ([]byte, error) {
+	variant, enc, padding, err := o.encoding()
+	if err != nil {
+		return nil, err
+	}
+	encoded = removeWhitespace(encoded, o.Lenient)
+
+	var check byte
+	switch variant {
+	case Base32Crockford:
+		encoded = normalizeCrockford(encoded)
+		if o.Checksum {
+			if encoded == "" {
+				return nil, fmt.Errorf("%s: missing check symbol", ErrInvalidBase32)
+			}
+			check = encoded[len(encoded)-1]
+			encoded = encoded[:len(encoded)-1]
+		}
+	case Base32Z:
+		encoded = strings.ToLower(encoded)
+	default:
+		encoded = strings.ToUpper(encoded)
+		if padding == PaddingNone || padding == PaddingOptional && len(encoded)%8 != 0 {
+			enc = enc.WithPadding(base32.NoPadding)
+		}
+	}
+
+	// A final group of 1, 3 or 6 symbols cannot be produced by any encoder, but the
+	// unpadded decoders accept it.
+	switch len(strings.TrimRight(encoded, "=")) % 8 {
+	case 1, 3, 6:
+		return nil, fmt.Errorf("%s: invalid length %d", ErrInvalidBase32, len(encoded))
+	}
+	decoded, err := enc.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrInvalidBase32, err)
+	}
+	if o.Checksum {
+		if want := crockfordCheckSymbols[crockfordChecksum(encoded)]; check != want {
+			return nil, fmt.Errorf("%s: check symbol %q does not match %q", ErrInvalidBase32, check, want)
+		}
+	}
+	return decoded, nil
+}
+
+// normalizeCrockford upper-cases Crockford base32 input, drops hyphens and maps the
+// easily confused letters I, L and O to the digits they stand for.
+func normalizeCrockford(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-':
+			return -1
+		case 'I', 'i', 'L', 'l':
+			return '1'
+		case 'O', 'o':
+			return '0'
+		}
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}, s)
+}
+
+// crockfordChecksum returns the value modulo 37 of the number spelled by valid
+// Crockford base32 symbols.
+func crockfordChecksum(symbols string) int {
+	m := 0
+	for i := 0; i < len(symbols); i++ {
+		m = (m*32 + strings.IndexByte(crockfordAlphabet, symbols[i])) % 37
+	}
+	return m
+}
+
+// EncodeBase32 converts the bytes of an ArrayBuffer, TypedArray or DataView to base32.
+// The options select the variant ("standard", "hex", "crockford" or "zbase32"), padding
+// and a Crockford check symbol.
+func (TextEncoding) EncodeBase32(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base32Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encode(data)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encoded)
+}
+
+// DecodeBase32 decodes base32 to an ArrayBuffer.
+// It takes the same options as EncodeBase32, plus lenient decoding.
+func (TextEncoding) DecodeBase32(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base32Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	decoded, err := o.decode(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(decoded))
+}
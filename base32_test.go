@@ -0,0 +1,134 @@
+package text_encoding
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBase32Encode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		opts     Base32Options
+		expected string
+	}{
+		{name: "empty", input: []byte{}, expected: ""},
+		{name: "standard", input: []byte("foobar"), expected: "MZXW6YTBOI======"},
+		{name: "standard unpadded", input: []byte("foobar"), opts: Base32Options{Padding: PaddingNone}, expected: "MZXW6YTBOI"},
+		{name: "hex", input: []byte("foobar"), opts: Base32Options{Variant: Base32Hex}, expected: "CPNMUOJ1E8======"},
+		{name: "crockford", input: []byte("foobar"), opts: Base32Options{Variant: Base32Crockford}, expected: "CSQPYRK1E8"},
+		{
+			name:     "crockford checksum",
+			input:    []byte("foobar"),
+			opts:     Base32Options{Variant: "Crockford", Checksum: true},
+			expected: "CSQPYRK1E8R",
+		},
+		{name: "z-base-32", input: []byte{0xF0, 0xBF, 0xC7}, opts: Base32Options{Variant: Base32Z}, expected: "6n9hq"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.opts.encode(tt.input)
+			if err != nil {
+				t.Fatalf("encode() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("encode(% X) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBase32Decode(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        Base32Options
+		expected    []byte
+		expectError bool
+	}{
+		{name: "standard", input: "MZXW6YTBOI======", expected: []byte("foobar")},
+		{name: "standard lower case", input: "mzxw6ytboi======", expected: []byte("foobar")},
+		{name: "standard missing padding", input: "MZXW6YTBOI", expectError: true},
+		{name: "standard optional padding", input: "MZXW6YTBOI", opts: Base32Options{Padding: PaddingOptional}, expected: []byte("foobar")},
+		{name: "standard optional with padding", input: "MZXW6YTBOI======", opts: Base32Options{Padding: PaddingOptional}, expected: []byte("foobar")},
+		{name: "standard no padding", input: "MZXW6YTBOI======", opts: Base32Options{Padding: PaddingNone}, expectError: true},
+		{name: "totp secret", input: "jbsw y3dp ehpk 3pxp", opts: Base32Options{Lenient: true, Padding: PaddingOptional}, expected: []byte("Hello!\xDE\xAD\xBE\xEF")},
+		{name: "spaces without lenient", input: "JBSW Y3DP", opts: Base32Options{Padding: PaddingOptional}, expectError: true},
+		{name: "hex", input: "CPNMUOJ1E8======", opts: Base32Options{Variant: Base32Hex}, expected: []byte("foobar")},
+		{name: "crockford", input: "CSQPYRK1E8", opts: Base32Options{Variant: Base32Crockford}, expected: []byte("foobar")},
+		{name: "crockford confusables", input: "csqp-yrkl-e8", opts: Base32Options{Variant: Base32Crockford}, expected: []byte("foobar")},
+		{name: "crockford checksum", input: "CSQPYRK1E8-R", opts: Base32Options{Variant: Base32Crockford, Checksum: true}, expected: []byte("foobar")},
+		{name: "crockford bad checksum", input: "CSQPYRK1E8S", opts: Base32Options{Variant: Base32Crockford, Checksum: true}, expectError: true},
+		{name: "crockford missing checksum", input: "", opts: Base32Options{Variant: Base32Crockford, Checksum: true}, expectError: true},
+		{name: "crockford invalid symbol", input: "CSQPU", opts: Base32Options{Variant: Base32Crockford}, expectError: true},
+		{name: "crockford invalid length", input: "CSQ", opts: Base32Options{Variant: Base32Crockford}, expectError: true},
+		{name: "z-base-32", input: "6n9hq", opts: Base32Options{Variant: Base32Z}, expected: []byte{0xF0, 0xBF, 0xC7}},
+		{name: "z-base-32 invalid symbol", input: "6n9hv", opts: Base32Options{Variant: Base32Z}, expectError: true},
+		{name: "padding on crockford", input: "CSQPYRK1E8", opts: Base32Options{Variant: Base32Crockford, Padding: PaddingRequired}, expectError: true},
+		{name: "checksum on standard", input: "MZXW6YTBOI======", opts: Base32Options{Checksum: true}, expectError: true},
+		{name: "unknown variant", input: "MZXW6YTBOI======", opts: Base32Options{Variant: "base36"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.opts.decode(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("decode(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode(%q) unexpected error: %v", tt.input, err)
+			}
+			if !bytes.Equal(result, tt.expected) {
+				t.Errorf("decode(%q) = % X, want % X", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBase32RoundTrip(t *testing.T) {
+	data := []byte{0x00, 0x01, 0x7F, 0x80, 0xFE, 0xFF, 0x10, 0x20, 0x30}
+	for _, variant := range []string{Base32Standard, Base32Hex, Base32Crockford, Base32Z} {
+		for n := 0; n <= len(data); n++ {
+			o := Base32Options{Variant: variant, Checksum: variant == Base32Crockford}
+			encoded, err := o.encode(data[:n])
+			if err != nil {
+				t.Fatalf("%s encode() unexpected error: %v", variant, err)
+			}
+			decoded, err := o.decode(encoded)
+			if err != nil {
+				t.Fatalf("%s decode(%q) unexpected error: %v", variant, encoded, err)
+			}
+			if !bytes.Equal(decoded, data[:n]) {
+				t.Errorf("%s round trip of % X = % X", variant, data[:n], decoded)
+			}
+		}
+	}
+}
+
+func TestBase32JS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const encoded = encoding.encodeBase32(new Uint8Array([0x66, 0x6F, 0x6F]), { variant: 'crockford', checksum: true });
+		const decoded = encoding.decodeBase32(encoded.toLowerCase(), { variant: 'crockford', checksum: true });
+		return encoded + '|' + (decoded instanceof ArrayBuffer) + '|' + Array.from(new Uint8Array(decoded)).join(',');
+	})()`).String()
+	if !strings.HasPrefix(result, "CSQPY") || !strings.HasSuffix(result, "|true|102,111,111") {
+		t.Errorf("base32 round trip = %q", result)
+	}
+
+	for _, script := range []string{
+		`encoding.decodeBase32('MZXW6YTBOI')`,
+		`encoding.decodeBase32(undefined, { padding: 'none' })`,
+		`encoding.encodeBase32(new Uint8Array(1), { variant: 'zbase32', padding: 'required' })`,
+	} {
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected error but got none", script)
+		}
+	}
+}
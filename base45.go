@@ -0,0 +1,127 @@
+package text_encoding
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// ErrInvalidBase45 is returned when Base45 input cannot be decoded.
+const ErrInvalidBase45 = "failed to decode base45"
+
+// base45Alphabet is the RFC 9285 alphabet, which matches the QR code alphanumeric mode.
+const base45Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// encodeBase45 writes each pair of bytes as three Base45 characters, least
+// significant first, and a final single byte as two characters.
+func encodeBase45(data []byte) string {
+	var sb strings.Builder
+	sb.Grow((len(data) + 1) / 2 * 3)
+	for i := 0; i < len(data); i += 2 {
+		if i+1 == len(data) {
+			n := int(data[i])
+			sb.WriteByte(base45Alphabet[n%45])
+			sb.WriteByte(base45Alphabet[n/45])
+			break
+		}
+		n := int(data[i])<<8 | int(data[i+1])
+		sb.WriteByte(base45Alphabet[n%45])
+		sb.WriteByte(base45Alphabet[n/45%45])
+		sb.WriteByte(base45Alphabet[n/(45*45)])
+	}
+	return sb.String()
+}
+
+// decodeBase45 reads characters written by encodeBase45 back into bytes.
+// Errors report the offset of the offending character or group.
+func decodeBase45(encoded string) ([]byte, error) {
+	out := make([]byte, 0, len(encoded)/3*2+1)
+	for start := 0; start < len(encoded); start += 3 {
+		end := min(start+3, len(encoded))
+		n, factor := 0, 1
+		for i := start; i < end; i++ {
+			digit := strings.IndexByte(base45Alphabet, encoded[i])
+			if digit < 0 {
+				return nil, fmt.Errorf("%s: invalid character %q at offset %d", ErrInvalidBase45, encoded[i], i)
+			}
+			n += digit * factor
+			factor *= 45
+		}
+
+		switch end - start {
+		case 1:
+			return nil, fmt.Errorf("%s: incomplete group at offset %d", ErrInvalidBase45, start)
+		case 2:
+			if n > 0xFF {
+				return nil, fmt.Errorf("%s: group at offset %d exceeds one byte", ErrInvalidBase45, start)
+			}
+			out = append(out, byte(n))
+			continue
+		}
+		if n > 0xFFFF {
+			return nil, fmt.Errorf("%s: group at offset %d exceeds two bytes", ErrInvalidBase45, start)
+		}
+		out = append(out, byte(n>>8), byte(n))
+	}
+	return out, nil
+}
+
+// EncodeBase45 converts the bytes of an ArrayBuffer, TypedArray or DataView to Base45
+// as specified by RFC 9285.
+func (TextEncoding) EncodeBase45(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encodeBase45(data))
+}
+
+// DecodeBase45 decodes Base45 to an ArrayBuffer.
+// Errors report the offset of the first invalid character or group.
+func (TextEncoding) DecodeBase45(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	decoded, err := decodeBase45(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(decoded))
+}
+
+// EncodeUTF8ToBase45 converts a string to UTF-8 bytes and then to Base45.
+func (TextEncoding) EncodeUTF8ToBase45(text string) (string, error) {
+	if err := validateInputSize(len(text)); err != nil {
+		return "", err
+	}
+	if err := validateUTF8String(text); err != nil {
+		return "", err
+	}
+	return encodeBase45([]byte(text)), nil
+}
+
+// DecodeUTF8FromBase45 decodes Base45 to UTF-8 text.
+// It validates both the Base45 encoding and the resulting UTF-8.
+func (TextEncoding) DecodeUTF8FromBase45(encoded string) (string, error) {
+	if err := validateInputSize(len(encoded)); err != nil {
+		return "", err
+	}
+	decoded, err := decodeBase45(encoded)
+	if err != nil {
+		return "", err
+	}
+	if err := validateUTF8Bytes(decoded); err != nil {
+		return "", errors.New(ErrInvalidDecodedUTF8)
+	}
+	return string(decoded), nil
+}
@@ -0,0 +1,93 @@
+package text_encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBase45(t *testing.T) {
+	te := &TextEncoding{}
+
+	// Examples from RFC 9285.
+	tests := []struct {
+		text    string
+		encoded string
+	}{
+		{text: "", encoded: ""},
+		{text: "AB", encoded: "BB8"},
+		{text: "Hello!!", encoded: "%69 VD92EX0"},
+		{text: "base-45", encoded: "UJCLQE7W581"},
+		{text: "ietf!", encoded: "QED8WEX0"},
+		{text: "é", encoded: "4XO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			encoded, err := te.EncodeUTF8ToBase45(tt.text)
+			if err != nil {
+				t.Fatalf("EncodeUTF8ToBase45() unexpected error: %v", err)
+			}
+			if encoded != tt.encoded {
+				t.Errorf("EncodeUTF8ToBase45(%q) = %q, want %q", tt.text, encoded, tt.encoded)
+			}
+			decoded, err := te.DecodeUTF8FromBase45(tt.encoded)
+			if err != nil {
+				t.Fatalf("DecodeUTF8FromBase45() unexpected error: %v", err)
+			}
+			if decoded != tt.text {
+				t.Errorf("DecodeUTF8FromBase45(%q) = %q, want %q", tt.encoded, decoded, tt.text)
+			}
+		})
+	}
+}
+
+func TestBase45Errors(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "lower case", input: "QED8wEX0", expected: "invalid character 'w' at offset 4"},
+		{name: "outside alphabet", input: "BB8#", expected: "invalid character '#' at offset 3"},
+		{name: "group overflow", input: "GGW", expected: "group at offset 0 exceeds two bytes"},
+		{name: "final group overflow", input: "BB8:6", expected: "group at offset 3 exceeds one byte"},
+		{name: "incomplete group", input: "BB8B", expected: "incomplete group at offset 3"},
+		{name: "invalid utf-8", input: "U5", expected: ErrInvalidDecodedUTF8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := te.DecodeUTF8FromBase45(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("DecodeUTF8FromBase45(%q) error = %v, want %q", tt.input, err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := te.EncodeUTF8ToBase45(string([]byte{0xFF})); err == nil {
+		t.Error("EncodeUTF8ToBase45() expected error for invalid UTF-8 input")
+	}
+	if _, err := te.DecodeUTF8FromBase45(strings.Repeat("0", MaxInputSize+1)); err == nil {
+		t.Error("DecodeUTF8FromBase45() expected error for oversized input")
+	}
+}
+
+func TestBase45JS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const encoded = encoding.encodeBase45(new Uint8Array([0x41, 0x42, 0xFF]));
+		const decoded = encoding.decodeBase45(encoded);
+		return encoded + '|' + (decoded instanceof ArrayBuffer) + '|' + Array.from(new Uint8Array(decoded)).join(',');
+	})()`).String()
+	expected := "BB8U5|true|65,66,255"
+	if result != expected {
+		t.Errorf("base45 = %q, want %q", result, expected)
+	}
+
+	if _, err := rt.RunString(`encoding.decodeBase45('GGW')`); err == nil {
+		t.Error("decodeBase45() expected error for an overflowing group")
+	}
+}
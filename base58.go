@@ -0,0 +1,311 @@
+package text_encoding
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// Error messages for Base58 and Base58Check
+const (
+	ErrInvalidBase58   = "failed to decode base58"
+	ErrBase58Checksum  = "base58check checksum mismatch"
+	ErrInvalidAlphabet = "invalid alphabet"
+)
+
+// Base58 alphabets for Base58Options.Alphabet.
+const (
+	// Base58Bitcoin is the alphabet used by Bitcoin addresses and IPFS. It is the default.
+	Base58Bitcoin = "bitcoin"
+	// Base58Flickr is the alphabet used by Flickr short URLs, with lower case letters first.
+	Base58Flickr = "flickr"
+)
+
+var (
+	bitcoinAlphabet = mustRadixAlphabet("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")
+	flickrAlphabet  = mustRadixAlphabet("123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ")
+)
+
+// base58CheckSumSize is the number of double-SHA256 bytes appended by Base58Check.
+const base58CheckSumSize = 4
+
+// Base58Options configures the Base58 and Base58Check encoders and decoders.
+type Base58Options struct {
+	Alphabet string `js:"alphabet"`
+}
+
+// Base58CheckResult is the result of DecodeBase58Check.
+type Base58CheckResult struct {
+	Version int               `js:"version"`
+	Payload sobek.ArrayBuffer `js:"payload"`
+}
+
+// alphabet resolves the Alphabet option.
+func (o Base58Options) alphabet() (*radixAlphabet, error) {
+	switch strings.ToLower(o.Alphabet) {
+	case "", Base58Bitcoin:
+		return bitcoinAlphabet, nil
+	case Base58Flickr:
+		return flickrAlphabet, nil
+	default:
+		return nil, fmt.Errorf("%s: alphabet must be %q or %q, got %q", ErrInvalidOption, Base58Bitcoin, Base58Flickr, o.Alphabet)
+	}
+}
+
+// radixAlphabet holds the digits of a positional number system and their values.
+type radixAlphabet struct {
+	digits string
+	values [256]int16
+}
+
+// newRadixAlphabet validates digits as an alphabet of 2 to 256 distinct ASCII characters.
+func newRadixAlphabet(digits string) (*radixAlphabet, error) {
+	if len(digits) < 2 || len(digits) > 256 {
+		return nil, fmt.Errorf("%s: must have between 2 and 256 characters, got %d", ErrInvalidAlphabet, len(digits))
+	}
+	a := &radixAlphabet{digits: digits}
+	for i := range a.values {
+		a.values[i] = -1
+	}
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if c >= 0x80 {
+			return nil, fmt.Errorf("%s: non-ASCII character at offset %d", ErrInvalidAlphabet, i)
+		}
+		if a.values[c] >= 0 {
+			return nil, fmt.Errorf("%s: duplicate character %q at offset %d", ErrInvalidAlphabet, c, i)
+		}
+		a.values[c] = int16(i)
+	}
+	return a, nil
+}
+
+// mustRadixAlphabet is newRadixAlphabet for alphabets known to be valid.
+func mustRadixAlphabet(digits string) *radixAlphabet {
+	a, err := newRadixAlphabet(digits)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// encode writes data as a big-endian number in the alphabet's radix.
+// Each leading zero byte is written as the zero digit, so leading zeros survive a round trip.
+func (a *radixAlphabet) encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+	radix := len(a.digits)
+
+	// digits holds the number in little-endian order; n of them are in use.
+	size := int(float64(len(data)-zeros)*8/math.Log2(float64(radix))) + 1
+	digits := make([]byte, size)
+	n := 0
+	for _, b := range data[zeros:] {
+		carry := int(b)
+		for i := 0; i < n; i++ {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % radix)
+			carry /= radix
+		}
+		for carry > 0 {
+			digits[n] = byte(carry % radix)
+			n++
+			carry /= radix
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(zeros + n)
+	for i := 0; i < zeros; i++ {
+		sb.WriteByte(a.digits[0])
+	}
+	for i := n - 1; i >= 0; i-- {
+		sb.WriteByte(a.digits[digits[i]])
+	}
+	return sb.String()
+}
+
+// decode reads a number written by encode back into bytes.
+func (a *radixAlphabet) decode(encoded string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(encoded) && encoded[zeros] == a.digits[0] {
+		zeros++
+	}
+	radix := len(a.digits)
+
+	// out holds the bytes in little-endian order.
+	out := make([]byte, 0, len(encoded))
+	for i := zeros; i < len(encoded); i++ {
+		value := a.values[encoded[i]]
+		if value < 0 {
+			return nil, fmt.Errorf("invalid character %q at offset %d", encoded[i], i)
+		}
+		carry := int(value)
+		for j := range out {
+			carry += int(out[j]) * radix
+			out[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			out = append(out, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	result := make([]byte, zeros+len(out))
+	for i, b := range out {
+		result[len(result)-1-i] = b
+	}
+	return result, nil
+}
+
+// base58Checksum returns the first four bytes of the double SHA-256 of data.
+func base58Checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:base58CheckSumSize]
+}
+
+// encode converts bytes to Base58.
+func (o Base58Options) encode(data []byte) (string, error) {
+	alphabet, err := o.alphabet()
+	if err != nil {
+		return "", err
+	}
+	return alphabet.encode(data), nil
+}
+
+// decode converts Base58 to bytes.
+func (o Base58Options) decode(encoded string) ([]byte, error) {
+	alphabet, err := o.alphabet()
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := alphabet.decode(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrInvalidBase58, err)
+	}
+	return decoded, nil
+}
+
+// encodeCheck converts a version byte and payload to Base58Check.
+func (o Base58Options) encodeCheck(payload []byte, version int64) (string, error) {
+	if version < 0 || version > 0xFF {
+		return "", fmt.Errorf("%s: version must be between 0 and 255, got %d", ErrInvalidOption, version)
+	}
+	data := make([]byte, 0, 1+len(payload)+base58CheckSumSize)
+	data = append(data, byte(version))
+	data = append(data, payload...)
+	data = append(data, base58Checksum(data)...)
+	return o.encode(data)
+}
+
+// decodeCheck converts Base58Check to its version byte and payload.
+func (o Base58Options) decodeCheck(encoded string) (int, []byte, error) {
+	data, err := o.decode(encoded)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 1+base58CheckSumSize {
+		return 0, nil, fmt.Errorf("%s: decoded data is %d bytes, need at least %d", ErrInvalidBase58, len(data), 1+base58CheckSumSize)
+	}
+	body, sum := data[:len(data)-base58CheckSumSize], data[len(data)-base58CheckSumSize:]
+	if !bytes.Equal(sum, base58Checksum(body)) {
+		return 0, nil, errors.New(ErrBase58Checksum)
+	}
+	return int(body[0]), body[1:], nil
+}
+
+// EncodeBase58 converts the bytes of an ArrayBuffer, TypedArray or DataView to Base58
+// using the Bitcoin alphabet, or the Flickr alphabet when selected in the options.
+// Leading zero bytes are kept as leading '1's.
+func (TextEncoding) EncodeBase58(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base58Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encode(data)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encoded)
+}
+
+// DecodeBase58 decodes Base58 to an ArrayBuffer.
+// It throws an error with the offset of the first character outside the alphabet.
+func (TextEncoding) DecodeBase58(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base58Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	decoded, err := o.decode(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(decoded))
+}
+
+// EncodeBase58Check converts a version byte and the bytes of an ArrayBuffer, TypedArray
+// or DataView to Base58Check, appending the first four bytes of the double SHA-256 of
+// both as a checksum.
+func (TextEncoding) EncodeBase58Check(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	payload, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(payload)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base58Options
+	if err := exportOptions(rt, call.Argument(2), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encodeCheck(payload, call.Argument(1).ToInteger())
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encoded)
+}
+
+// DecodeBase58Check converts Base58Check to its version byte and its payload as an
+// ArrayBuffer. It throws if the checksum does not match.
+func (TextEncoding) DecodeBase58Check(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base58Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	version, payload, err := o.decodeCheck(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(&Base58CheckResult{Version: version, Payload: rt.NewArrayBuffer(payload)})
+}
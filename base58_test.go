@@ -0,0 +1,157 @@
+package text_encoding
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		opts     Base58Options
+		expected string
+	}{
+		{name: "empty", input: []byte{}, expected: ""},
+		{name: "text", input: []byte("Hello World!"), expected: "2NEpo7TZRRrLZSi2U"},
+		{name: "leading zeros", input: []byte{0, 0, 0x28, 0x7F, 0xB4, 0xCD}, expected: "11233QC4"},
+		{name: "only zeros", input: []byte{0, 0, 0}, expected: "111"},
+		{name: "single byte", input: []byte{0x39}, expected: "z"},
+		{name: "flickr", input: []byte("Hello World!"), opts: Base58Options{Alphabet: Base58Flickr}, expected: "2nePN7syqqRkyrH2t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.opts.encode(tt.input)
+			if err != nil {
+				t.Fatalf("encode() unexpected error: %v", err)
+			}
+			if encoded != tt.expected {
+				t.Errorf("encode(% X) = %q, want %q", tt.input, encoded, tt.expected)
+			}
+			decoded, err := tt.opts.decode(tt.expected)
+			if err != nil {
+				t.Fatalf("decode() unexpected error: %v", err)
+			}
+			if !bytes.Equal(decoded, tt.input) {
+				t.Errorf("decode(%q) = % X, want % X", tt.expected, decoded, tt.input)
+			}
+		})
+	}
+}
+
+func TestBase58Errors(t *testing.T) {
+	_, err := Base58Options{}.decode("2NEp0")
+	if err == nil || !strings.Contains(err.Error(), ErrInvalidBase58) || !strings.Contains(err.Error(), "'0' at offset 4") {
+		t.Errorf("decode() error = %v, want invalid character '0' at offset 4", err)
+	}
+	if _, err := (Base58Options{Alphabet: "ripple"}).decode("abc"); err == nil {
+		t.Error("decode() expected error for unknown alphabet")
+	}
+}
+
+func TestBase58Check(t *testing.T) {
+	var o Base58Options
+
+	// The address of the Bitcoin genesis block coinbase output.
+	payload, _ := hex.DecodeString("62e907b15cbf27d5425399ebf6f0fb50ebb88f18")
+	const address = "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+
+	encoded, err := o.encodeCheck(payload, 0)
+	if err != nil {
+		t.Fatalf("encodeCheck() unexpected error: %v", err)
+	}
+	if encoded != address {
+		t.Errorf("encodeCheck() = %q, want %q", encoded, address)
+	}
+
+	version, decoded, err := o.decodeCheck(address)
+	if err != nil {
+		t.Fatalf("decodeCheck() unexpected error: %v", err)
+	}
+	if version != 0 || !bytes.Equal(decoded, payload) {
+		t.Errorf("decodeCheck() = %d, % X, want 0, % X", version, decoded, payload)
+	}
+
+	encoded, err = o.encodeCheck([]byte("payload"), 0x80)
+	if err != nil {
+		t.Fatalf("encodeCheck() unexpected error: %v", err)
+	}
+	version, decoded, err = o.decodeCheck(encoded)
+	if err != nil {
+		t.Fatalf("decodeCheck() unexpected error: %v", err)
+	}
+	if version != 0x80 || string(decoded) != "payload" {
+		t.Errorf("decodeCheck() = %d, %q, want 128, %q", version, decoded, "payload")
+	}
+
+	// Changing one character breaks the checksum.
+	_, _, err = o.decodeCheck("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb")
+	if err == nil || err.Error() != ErrBase58Checksum {
+		t.Errorf("decodeCheck() error = %v, want %q", err, ErrBase58Checksum)
+	}
+	if _, _, err := o.decodeCheck("1111"); err == nil {
+		t.Error("decodeCheck() expected error for short input")
+	}
+	if _, _, err := o.decodeCheck("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfN0"); err == nil || !strings.Contains(err.Error(), ErrInvalidBase58) {
+		t.Errorf("decodeCheck() error = %v, want %q", err, ErrInvalidBase58)
+	}
+	if _, err := o.encodeCheck(payload, 256); err == nil {
+		t.Error("encodeCheck() expected error for version out of range")
+	}
+}
+
+func TestRadixAlphabet(t *testing.T) {
+	if _, err := newRadixAlphabet("0"); err == nil {
+		t.Error("newRadixAlphabet() expected error for a single character")
+	}
+	if _, err := newRadixAlphabet("0123456789abcdef0"); err == nil || !strings.Contains(err.Error(), "duplicate character '0' at offset 16") {
+		t.Errorf("newRadixAlphabet() error = %v, want duplicate character", err)
+	}
+	if _, err := newRadixAlphabet("01é"); err == nil {
+		t.Error("newRadixAlphabet() expected error for non-ASCII characters")
+	}
+
+	binary := mustRadixAlphabet("01")
+	if encoded := binary.encode([]byte{0, 5}); encoded != "0101" {
+		t.Errorf("encode() = %q, want %q", encoded, "0101")
+	}
+}
+
+func TestBase58JS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const encoded = encoding.encodeBase58(new Uint8Array([0, 0, 0x28, 0x7F, 0xB4, 0xCD]));
+		const decoded = encoding.decodeBase58(encoded);
+		const check = encoding.decodeBase58Check('1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa');
+		const address = encoding.encodeBase58Check(new Uint8Array(check.payload), check.version, { alphabet: 'bitcoin' });
+		return [
+			encoded,
+			decoded instanceof ArrayBuffer,
+			decoded.byteLength,
+			check.version,
+			check.payload instanceof ArrayBuffer,
+			check.payload.byteLength,
+			address,
+		].join('|');
+	})()`).String()
+	expected := "11233QC4|true|6|0|true|20|1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+	if result != expected {
+		t.Errorf("base58 = %q, want %q", result, expected)
+	}
+
+	throwing := []string{
+		`encoding.encodeBase58('abc')`,
+		`encoding.encodeBase58([1, 2, 3])`,
+		`encoding.encodeBase58Check('abc', 0)`,
+		`encoding.decodeBase58()`,
+	}
+	for _, script := range throwing {
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected to throw", script)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package text_encoding
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// Base64 alphabets for Base64Options.Alphabet.
+const (
+	// Base64Standard is the RFC 4648 alphabet with '+' and '/'. It is the default.
+	Base64Standard = "standard"
+	// Base64URL is the RFC 4648 URL and filename safe alphabet with '-' and '_'.
+	Base64URL = "url"
+)
+
+// Padding modes for the Padding field of the encoding options.
+const (
+	// PaddingRequired writes padding when encoding and requires it when decoding. It is the default.
+	PaddingRequired = "required"
+	// PaddingOptional writes padding when encoding and accepts input with or without it.
+	PaddingOptional = "optional"
+	// PaddingNone writes no padding and rejects padded input.
+	PaddingNone = "none"
+)
+
+// mimeLineLength is the maximum encoded line length of RFC 2045 MIME bodies.
+const mimeLineLength = 76
+
+// Base64Options configures the base64 encoders and decoders.
+// MIME wraps encoded output in 76-column lines separated by CRLF. Lenient ignores
+// spaces and tabs in encoded input; line breaks are always ignored. Strict rejects
+// encoded input whose unused trailing bits are not zero.
+type Base64Options struct {
+	Alphabet string `js:"alphabet"`
+	Padding  string `js:"padding"`
+	MIME     bool   `js:"mime"`
+	Lenient  bool   `js:"lenient"`
+	Strict   bool   `js:"strict"`
+}
+
+// base64Options returns the first of opts, or the zero options.
+func base64Options(opts []Base64Options) Base64Options {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return Base64Options{}
+}
+
+// encoding resolves the alphabet option, with padding and strictness applied.
+func (o Base64Options) encoding(padded bool) (*base64.Encoding, error) {
+	var enc *base64.Encoding
+	switch strings.ToLower(o.Alphabet) {
+	case "", Base64Standard:
+		enc = base64.StdEncoding
+	case Base64URL:
+		enc = base64.URLEncoding
+	default:
+		return nil, fmt.Errorf("%s: alphabet must be %q or %q, got %q", ErrInvalidOption, Base64Standard, Base64URL, o.Alphabet)
+	}
+	if !padded {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	if o.Strict {
+		enc = enc.Strict()
+	}
+	return enc, nil
+}
+
+// paddingMode resolves a padding option to one of the padding modes.
+func paddingMode(padding string) (string, error) {
+	switch mode := strings.ToLower(padding); mode {
+	case "":
+		return PaddingRequired, nil
+	case PaddingRequired, PaddingOptional, PaddingNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%s: padding must be one of %q, %q or %q, got %q",
+			ErrInvalidOption, PaddingRequired, PaddingOptional, PaddingNone, padding)
+	}
+}
+
+// encode converts data to base64 as configured.
+func (o Base64Options) encode(data []byte) (string, error) {
+	padding, err := paddingMode(o.Padding)
+	if err != nil {
+		return "", err
+	}
+	enc, err := o.encoding(padding != PaddingNone)
+	if err != nil {
+		return "", err
+	}
+	encoded := enc.EncodeToString(data)
+	if o.MIME {
+		encoded = wrapLines(encoded, mimeLineLength)
+	}
+	return encoded, nil
+}
+
+// decode converts base64 to bytes as configured.
+func (o Base64Options) decode(encoded string) ([]byte, error) {
+	padding, err := paddingMode(o.Padding)
+	if err != nil {
+		return nil, err
+	}
+	encoded = removeWhitespace(encoded, o.Lenient)
+
+	padded := padding == PaddingRequired || padding == PaddingOptional && len(encoded)%4 == 0
+	enc, err := o.encoding(padded)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := enc.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrInvalidBase64, err)
+	}
+	return decoded, nil
+}
+
+// EncodeBase64 converts the bytes of an ArrayBuffer, TypedArray or DataView to base64.
+// Unlike EncodeUTF8ToBase64 it accepts arbitrary binary data. It takes the same
+// options as EncodeUTF8ToBase64.
+func (TextEncoding) EncodeBase64(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base64Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encode(data)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encoded)
+}
+
+// DecodeBase64 decodes base64 to an ArrayBuffer.
+// Unlike DecodeUTF8FromBase64 the decoded bytes need not be valid UTF-8. It takes
+// the same options as DecodeUTF8FromBase64.
+func (TextEncoding) DecodeBase64(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base64Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	decoded, err := o.decode(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(decoded))
+}
+
+// wrapLines splits s into lines of at most width characters separated by CRLF.
+func wrapLines(s string, width int) string {
+	if len(s) <= width {
+		return s
+	}
+	var sb strings.Builder
+	sb.Grow(len(s) + 2*(len(s)/width))
+	for len(s) > width {
+		sb.WriteString(s[:width])
+		sb.WriteString("\r\n")
+		s = s[width:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// removeWhitespace drops line breaks from s, and spaces and tabs as well when all is set.
+func removeWhitespace(s string, all bool) string {
+	isSpace := func(r rune) bool {
+		switch r {
+		case '\r', '\n':
+			return true
+		case ' ', '\t', '\f', '\v':
+			return all
+		}
+		return false
+	}
+	if strings.IndexFunc(s, isSpace) < 0 {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if isSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,230 @@
+package text_encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeUTF8ToBase64Options(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     Base64Options
+		expected string
+	}{
+		{name: "default", input: "??>", expected: "Pz8+"},
+		{name: "url alphabet", input: "??>", opts: Base64Options{Alphabet: Base64URL}, expected: "Pz8-"},
+		{name: "padding", input: "hi", expected: "aGk="},
+		{name: "optional padding", input: "hi", opts: Base64Options{Padding: PaddingOptional}, expected: "aGk="},
+		{name: "no padding", input: "hi", opts: Base64Options{Padding: PaddingNone}, expected: "aGk"},
+		{
+			name:     "url without padding",
+			input:    "{\"alg\":\"HS256\"}",
+			opts:     Base64Options{Alphabet: "URL", Padding: PaddingNone},
+			expected: "eyJhbGciOiJIUzI1NiJ9",
+		},
+		{
+			name:     "mime short line",
+			input:    "hello",
+			opts:     Base64Options{MIME: true},
+			expected: "aGVsbG8=",
+		},
+		{
+			name:     "mime wrapping",
+			input:    strings.Repeat("a", 60),
+			opts:     Base64Options{MIME: true},
+			expected: strings.Repeat("YWFh", 19) + "\r\n" + "YWFh",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.EncodeUTF8ToBase64(tt.input, tt.opts)
+			if err != nil {
+				t.Fatalf("EncodeUTF8ToBase64() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("EncodeUTF8ToBase64() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeUTF8FromBase64Options(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name        string
+		input       string
+		opts        Base64Options
+		expected    string
+		expectError bool
+	}{
+		{name: "default", input: "Pz8+", expected: "??>"},
+		{name: "url alphabet", input: "Pz8-", opts: Base64Options{Alphabet: Base64URL}, expected: "??>"},
+		{name: "url character in standard", input: "Pz8-", expectError: true},
+		{name: "missing padding", input: "aGk", expectError: true},
+		{name: "optional padding with padding", input: "aGk=", opts: Base64Options{Padding: PaddingOptional}, expected: "hi"},
+		{name: "optional padding without padding", input: "aGk", opts: Base64Options{Padding: PaddingOptional}, expected: "hi"},
+		{name: "optional padding partial", input: "aG=", opts: Base64Options{Padding: PaddingOptional}, expectError: true},
+		{name: "no padding", input: "aGk", opts: Base64Options{Padding: PaddingNone}, expected: "hi"},
+		{name: "no padding with padding", input: "aGk=", opts: Base64Options{Padding: PaddingNone}, expectError: true},
+		{
+			name:     "jwt segment",
+			input:    "eyJhbGciOiJIUzI1NiJ9",
+			opts:     Base64Options{Alphabet: Base64URL, Padding: PaddingOptional},
+			expected: "{\"alg\":\"HS256\"}",
+		},
+		{name: "line breaks", input: "aGVs\r\nbG8=", expected: "hello"},
+		{name: "spaces", input: "aGVs bG8=", expectError: true},
+		{name: "lenient spaces", input: " aGVs\tbG8=\n", opts: Base64Options{Lenient: true}, expected: "hello"},
+		{name: "non-canonical trailing bits", input: "aGl=", expected: "hi"},
+		{name: "strict trailing bits", input: "aGl=", opts: Base64Options{Strict: true}, expectError: true},
+		{name: "strict canonical", input: "aGk=", opts: Base64Options{Strict: true}, expected: "hi"},
+		{name: "unknown alphabet", input: "aGk=", opts: Base64Options{Alphabet: "base58"}, expectError: true},
+		{name: "unknown padding", input: "aGk=", opts: Base64Options{Padding: "sometimes"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.DecodeUTF8FromBase64(tt.input, tt.opts)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("DecodeUTF8FromBase64(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeUTF8FromBase64(%q) unexpected error: %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("DecodeUTF8FromBase64(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBase64OptionsRoundTrip(t *testing.T) {
+	te := &TextEncoding{}
+	text := strings.Repeat("Hello 🌍 你好 ", 20)
+
+	for _, opts := range []Base64Options{
+		{},
+		{Alphabet: Base64URL, Padding: PaddingNone},
+		{MIME: true},
+	} {
+		encoded, err := te.EncodeUTF8ToBase64(text, opts)
+		if err != nil {
+			t.Fatalf("EncodeUTF8ToBase64(%+v) unexpected error: %v", opts, err)
+		}
+		decoded, err := te.DecodeUTF8FromBase64(encoded, opts)
+		if err != nil {
+			t.Fatalf("DecodeUTF8FromBase64(%+v) unexpected error: %v", opts, err)
+		}
+		if decoded != text {
+			t.Errorf("round trip with %+v = %q, want %q", opts, decoded, text)
+		}
+	}
+
+	result, err := te.DecodeUTF8FromBase64WithReplacement("b2v_", Base64Options{Alphabet: Base64URL})
+	if err != nil {
+		t.Fatalf("DecodeUTF8FromBase64WithReplacement() unexpected error: %v", err)
+	}
+	if result.Text != "ok\uFFFD" {
+		t.Errorf("DecodeUTF8FromBase64WithReplacement() = %q, want %q", result.Text, "ok\uFFFD")
+	}
+}
+
+func TestBase64OptionsJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const opts = { alphabet: 'url', padding: 'none' };
+		const encoded = encoding.encodeUTF8ToBase64('??>', opts);
+		return encoded + '|' + encoding.decodeUTF8FromBase64(encoded, opts);
+	})()`).String()
+	expected := "Pz8-|??>"
+	if result != expected {
+		t.Errorf("base64 options = %q, want %q", result, expected)
+	}
+}
+
+func TestBinaryBase64JS(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{
+			name:     "encode uint8array",
+			script:   `encoding.encodeBase64(new Uint8Array([0x00, 0xFF, 0xFE, 0x80]))`,
+			expected: "AP/+gA==",
+		},
+		{
+			name:     "encode arraybuffer",
+			script:   `encoding.encodeBase64(new Uint8Array([0xFB, 0xFF]).buffer, { alphabet: 'url', padding: 'none' })`,
+			expected: "-_8",
+		},
+		{
+			name:     "encode subarray",
+			script:   `encoding.encodeBase64(new Uint8Array([1, 0xFF, 0xFF, 0xFF, 2]).subarray(1, 4))`,
+			expected: "////",
+		},
+		{
+			name:     "encode dataview",
+			script:   `encoding.encodeBase64(new DataView(new Uint16Array([0xFFFE]).buffer))`,
+			expected: "/v8=",
+		},
+		{
+			name:     "encode empty",
+			script:   `encoding.encodeBase64(new Uint8Array(0))`,
+			expected: "",
+		},
+		{
+			name: "decode to arraybuffer",
+			script: `(() => {
+				const buf = encoding.decodeBase64('AP/+gA==');
+				return (buf instanceof ArrayBuffer) + ':' + Array.from(new Uint8Array(buf)).join(',');
+			})()`,
+			expected: "true:0,255,254,128",
+		},
+		{
+			name:     "decode with options",
+			script:   `Array.from(new Uint8Array(encoding.decodeBase64('-_8', { alphabet: 'url', padding: 'optional' }))).join(',')`,
+			expected: "251,255",
+		},
+		{
+			name:     "round trip",
+			script:   `Array.from(new Uint8Array(encoding.decodeBase64(encoding.encodeBase64(new Uint8Array([0xC3, 0x28, 0, 7]), { mime: true })))).join(',')`,
+			expected: "195,40,0,7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newTestRuntime(t)
+			result := runScript(t, rt, tt.script).String()
+			if result != tt.expected {
+				t.Errorf("%s = %q, want %q", tt.script, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBinaryBase64ErrorsJS(t *testing.T) {
+	scripts := []string{
+		`encoding.encodeBase64('not bytes')`,
+		`encoding.encodeBase64(new Uint8Array(1), { alphabet: 'base58' })`,
+		`encoding.decodeBase64('!!!!')`,
+		`encoding.decodeBase64('aGl=', { strict: true })`,
+		`encoding.decodeBase64('aGk=', { padding: 'none' })`,
+	}
+	for _, script := range scripts {
+		rt := newTestRuntime(t)
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected error but got none", script)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package text_encoding
+
+import (
+	"encoding/ascii85"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// ErrInvalidBase85 is returned when base85 input cannot be decoded.
+const ErrInvalidBase85 = "failed to decode base85"
+
+// Base85 variants for Base85Options.Variant.
+const (
+	// Base85ASCII85 is btoa and Adobe Ascii85, with 'z' for groups of four zero bytes. It is the default.
+	Base85ASCII85 = "ascii85"
+	// Base85Z85 is ZeroMQ's Z85, which only encodes data whose length is a multiple of four.
+	Base85Z85 = "z85"
+	// Base85RFC1924 uses the RFC 1924 alphabet, as git binary patches and Python's b85encode do.
+	Base85RFC1924 = "rfc1924"
+)
+
+var (
+	z85Alphabet     = newBase85Alphabet("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#")
+	rfc1924Alphabet = newBase85Alphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~")
+)
+
+// Base85Options configures the base85 encoders and decoders.
+// With Delimiters set, Ascii85 is wrapped in the Adobe "<~" and "~>" delimiters when
+// encoding, and they are required when decoding. Ascii85 decoding always accepts the
+// delimiters and ignores whitespace.
+type Base85Options struct {
+	Variant    string `js:"variant"`
+	Delimiters bool   `js:"delimiters"`
+}
+
+// variant resolves the Variant option.
+func (o Base85Options) variant() (string, error) {
+	variant := strings.ToLower(o.Variant)
+	switch variant {
+	case "":
+		variant = Base85ASCII85
+	case Base85ASCII85, Base85Z85, Base85RFC1924:
+	default:
+		return "", fmt.Errorf("%s: variant must be one of %q, %q or %q, got %q",
+			ErrInvalidOption, Base85ASCII85, Base85Z85, Base85RFC1924, o.Variant)
+	}
+	if o.Delimiters && variant != Base85ASCII85 {
+		return "", fmt.Errorf("%s: delimiters require the %q variant", ErrInvalidOption, Base85ASCII85)
+	}
+	return variant, nil
+}
+
+// encode converts data to base85 as configured.
+func (o Base85Options) encode(data []byte) (string, error) {
+	variant, err := o.variant()
+	if err != nil {
+		return "", err
+	}
+	switch variant {
+	case Base85Z85:
+		if len(data)%4 != 0 {
+			return "", fmt.Errorf("%s: z85 input length must be a multiple of 4, got %d", ErrInvalidOption, len(data))
+		}
+		return z85Alphabet.encode(data), nil
+	case Base85RFC1924:
+		return rfc1924Alphabet.encode(data), nil
+	}
+
+	out := make([]byte, ascii85.MaxEncodedLen(len(data)))
+	encoded := string(out[:ascii85.Encode(out, data)])
+	if o.Delimiters {
+		encoded = "<~" + encoded + "~>"
+	}
+	return encoded, nil
+}
+
+// decode converts base85 to bytes as configured.
+func (o Base85Options) decode(encoded string) ([]byte, error) {
+	variant, err := o.variant()
+	if err != nil {
+		return nil, err
+	}
+	switch variant {
+	case Base85Z85:
+		if len(encoded)%5 != 0 {
+			return nil, fmt.Errorf("%s: z85 input length must be a multiple of 5, got %d", ErrInvalidBase85, len(encoded))
+		}
+		return z85Alphabet.decode(encoded)
+	case Base85RFC1924:
+		return rfc1924Alphabet.decode(encoded)
+	}
+
+	trimmed := strings.TrimSpace(encoded)
+	hasDelimiters := strings.HasPrefix(trimmed, "<~") && strings.HasSuffix(trimmed, "~>") && len(trimmed) >= 4
+	if hasDelimiters {
+		trimmed = trimmed[2 : len(trimmed)-2]
+	} else if o.Delimiters {
+		return nil, fmt.Errorf("%s: missing \"<~\" and \"~>\" delimiters", ErrInvalidBase85)
+	}
+	out := make([]byte, 4*len(trimmed))
+	n, _, err := ascii85.Decode(out, []byte(trimmed), true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrInvalidBase85, err)
+	}
+	return out[:n], nil
+}
+
+// base85Alphabet holds the digits of a base85 alphabet and their values.
+type base85Alphabet struct {
+	digits string
+	values [256]int8
+}
+
+// newBase85Alphabet indexes 85 distinct ASCII digits.
+func newBase85Alphabet(digits string) *base85Alphabet {
+	a := &base85Alphabet{digits: digits}
+	for i := range a.values {
+		a.values[i] = -1
+	}
+	for i := 0; i < len(digits); i++ {
+		a.values[digits[i]] = int8(i)
+	}
+	return a
+}
+
+// encode writes each group of four bytes as five big-endian base85 digits.
+// A final group of n bytes is written as n+1 digits.
+func (a *base85Alphabet) encode(data []byte) string {
+	var sb strings.Builder
+	sb.Grow((len(data) + 3) / 4 * 5)
+	for len(data) > 0 {
+		var group [4]byte
+		n := copy(group[:], data)
+		data = data[n:]
+
+		value := binary.BigEndian.Uint32(group[:])
+		var digits [5]byte
+		for i := 4; i >= 0; i-- {
+			digits[i] = a.digits[value%85]
+			value /= 85
+		}
+		sb.Write(digits[:n+1])
+	}
+	return sb.String()
+}
+
+// decode reads digits written by encode back into bytes. A final group of n digits is
+// padded with the highest digit and yields n-1 bytes.
+func (a *base85Alphabet) decode(encoded string) ([]byte, error) {
+	out := make([]byte, 0, (len(encoded)+4)/5*4)
+	for start := 0; start < len(encoded); start += 5 {
+		end := min(start+5, len(encoded))
+		if end-start == 1 {
+			return nil, fmt.Errorf("%s: incomplete group at offset %d", ErrInvalidBase85, start)
+		}
+
+		var value uint64
+		for i := start; i < start+5; i++ {
+			digit := int8(84)
+			if i < end {
+				digit = a.values[encoded[i]]
+				if digit < 0 {
+					return nil, fmt.Errorf("%s: invalid character %q at offset %d", ErrInvalidBase85, encoded[i], i)
+				}
+			}
+			value = value*85 + uint64(digit)
+		}
+		if value > 0xFFFFFFFF {
+			return nil, fmt.Errorf("%s: group at offset %d overflows 32 bits", ErrInvalidBase85, start)
+		}
+
+		var group [4]byte
+		binary.BigEndian.PutUint32(group[:], uint32(value))
+		out = append(out, group[:end-start-1]...)
+	}
+	return out, nil
+}
+
+// EncodeBase85 converts the bytes of an ArrayBuffer, TypedArray or DataView to base85.
+// The options select the variant ("ascii85", "z85" or "rfc1924") and Adobe delimiters.
+func (TextEncoding) EncodeBase85(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base85Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encode(data)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encoded)
+}
+
+// DecodeBase85 decodes base85 to an ArrayBuffer.
+// It takes the same options as EncodeBase85.
+func (TextEncoding) DecodeBase85(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Base85Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	decoded, err := o.decode(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(decoded))
+}
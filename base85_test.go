@@ -0,0 +1,125 @@
+package text_encoding
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBase85(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		opts     Base85Options
+		expected string
+	}{
+		{name: "ascii85 empty", input: []byte{}, expected: ""},
+		{name: "ascii85", input: []byte("hello"), expected: "BOu!rDZ"},
+		{name: "ascii85 zero group", input: []byte("\x00\x00\x00\x00ab"), expected: "z@:B"},
+		{name: "adobe", input: []byte("hello"), opts: Base85Options{Delimiters: true}, expected: "<~BOu!rDZ~>"},
+		{name: "z85", input: []byte{0x86, 0x4F, 0xD2, 0x6F, 0xB5, 0x59, 0xF7, 0x5B}, opts: Base85Options{Variant: Base85Z85}, expected: "HelloWorld"},
+		{name: "rfc1924", input: []byte("hello"), opts: Base85Options{Variant: Base85RFC1924}, expected: "Xk~0{Zv"},
+		{name: "rfc1924 max group", input: []byte{0xFF, 0xFF, 0xFF, 0xFF}, opts: Base85Options{Variant: "RFC1924"}, expected: "|NsC0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.opts.encode(tt.input)
+			if err != nil {
+				t.Fatalf("encode() unexpected error: %v", err)
+			}
+			if encoded != tt.expected {
+				t.Errorf("encode(% X) = %q, want %q", tt.input, encoded, tt.expected)
+			}
+			decoded, err := tt.opts.decode(tt.expected)
+			if err != nil {
+				t.Fatalf("decode(%q) unexpected error: %v", tt.expected, err)
+			}
+			if !bytes.Equal(decoded, tt.input) {
+				t.Errorf("decode(%q) = % X, want % X", tt.expected, decoded, tt.input)
+			}
+		})
+	}
+}
+
+func TestBase85Decode(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        Base85Options
+		expected    []byte
+		expectError string
+	}{
+		{name: "ascii85 delimiters accepted", input: "<~BOu!rDZ~>", expected: []byte("hello")},
+		{name: "ascii85 whitespace", input: " BOu!\n rDZ ", expected: []byte("hello")},
+		{name: "ascii85 missing delimiters", input: "BOu!rDZ", opts: Base85Options{Delimiters: true}, expectError: "missing"},
+		{name: "ascii85 invalid character", input: "BOu!v", expectError: ErrInvalidBase85},
+		{name: "z85 single group", input: "Hello", opts: Base85Options{Variant: Base85Z85}, expected: []byte{0x86, 0x4F, 0xD2, 0x6F}},
+		{name: "z85 partial group", input: "HelloWor", opts: Base85Options{Variant: Base85Z85}, expectError: "multiple of 5"},
+		{name: "z85 invalid character", input: "Hel~o", opts: Base85Options{Variant: Base85Z85}, expectError: "invalid character '~' at offset 3"},
+		{name: "rfc1924 overflow", input: "|NsC1", opts: Base85Options{Variant: Base85RFC1924}, expectError: "overflows"},
+		{name: "rfc1924 incomplete group", input: "Xk~0{Z", opts: Base85Options{Variant: Base85RFC1924}, expectError: "incomplete group at offset 5"},
+		{name: "delimiters on z85", input: "Hello", opts: Base85Options{Variant: Base85Z85, Delimiters: true}, expectError: ErrInvalidOption},
+		{name: "unknown variant", input: "Hello", opts: Base85Options{Variant: "btoa"}, expectError: ErrInvalidOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := tt.opts.decode(tt.input)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("decode(%q) error = %v, want %q", tt.input, err, tt.expectError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode(%q) unexpected error: %v", tt.input, err)
+			}
+			if !bytes.Equal(decoded, tt.expected) {
+				t.Errorf("decode(%q) = % X, want % X", tt.input, decoded, tt.expected)
+			}
+		})
+	}
+
+	if _, err := (Base85Options{Variant: Base85Z85}).encode([]byte("abc")); err == nil {
+		t.Error("encode() expected error for z85 input that is not a multiple of 4 bytes")
+	}
+}
+
+func TestBase85RoundTrip(t *testing.T) {
+	data := []byte{0x00, 0x00, 0x00, 0x00, 0xFF, 0xFE, 0x80, 0x01, 0x7F, 0x20, 0x00, 0x10}
+	for _, variant := range []string{Base85ASCII85, Base85RFC1924} {
+		for n := 0; n <= len(data); n++ {
+			o := Base85Options{Variant: variant}
+			encoded, err := o.encode(data[:n])
+			if err != nil {
+				t.Fatalf("%s encode() unexpected error: %v", variant, err)
+			}
+			decoded, err := o.decode(encoded)
+			if err != nil {
+				t.Fatalf("%s decode(%q) unexpected error: %v", variant, encoded, err)
+			}
+			if !bytes.Equal(decoded, data[:n]) {
+				t.Errorf("%s round trip of % X = % X", variant, data[:n], decoded)
+			}
+		}
+	}
+}
+
+func TestBase85JS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const encoded = encoding.encodeBase85(new Uint8Array([0x86, 0x4F, 0xD2, 0x6F, 0xB5, 0x59, 0xF7, 0x5B]), { variant: 'z85' });
+		const decoded = encoding.decodeBase85('<~BOu!rDZ~>', { delimiters: true });
+		return encoded + '|' + (decoded instanceof ArrayBuffer) + '|' + encoding.decodeUTF8(new Uint8Array(decoded));
+	})()`).String()
+	expected := "HelloWorld|true|hello"
+	if result != expected {
+		t.Errorf("base85 = %q, want %q", result, expected)
+	}
+
+	if _, err := rt.RunString(`encoding.decodeBase85('Hel~o', { variant: 'z85' })`); err == nil {
+		t.Error("decodeBase85() expected error for invalid z85")
+	}
+}
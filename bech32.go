@@ -0,0 +1,347 @@
+package text_encoding
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// Error messages for Bech32 and Bech32m
+const (
+	ErrInvalidBech32   = "invalid bech32 string"
+	ErrBech32Character = "invalid bech32 character"
+	ErrBech32Checksum  = "invalid bech32 checksum"
+	ErrConvertBits     = "failed to convert bits"
+)
+
+// Bech32 checksum variants for Bech32Options.Variant.
+const (
+	// Bech32 is the original BIP 173 checksum, used by SegWit version 0 addresses. It is the default.
+	Bech32 = "bech32"
+	// Bech32m is the BIP 350 checksum, used by SegWit version 1 and later addresses.
+	Bech32m = "bech32m"
+)
+
+const (
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	// bech32DefaultMaxLength is the BIP 173 limit on the length of a whole string.
+	bech32DefaultMaxLength = 90
+	bech32ChecksumLength   = 6
+)
+
+// bech32Constants are the values the checksum of each variant leaves in the polymod.
+var bech32Constants = map[string]uint32{
+	Bech32:  1,
+	Bech32m: 0x2bc830a3,
+}
+
+// Bech32Options configures the Bech32 encoder and decoder.
+// Variant is "bech32" or "bech32m"; when decoding, an empty variant accepts either.
+// MaxLength overrides the 90 character limit of BIP 173. With Words set, the data is
+// made of 5-bit words that are used as they are instead of being regrouped from bytes,
+// which SegWit addresses need for their witness version.
+type Bech32Options struct {
+	Variant   string `js:"variant"`
+	MaxLength int    `js:"maxLength"`
+	Words     bool   `js:"words"`
+}
+
+// Bech32Result is the result of DecodeBech32.
+// Words is the payload as 5-bit words. Data is the payload regrouped into bytes, and is
+// null when the words option is set.
+type Bech32Result struct {
+	HRP     string            `js:"hrp"`
+	Data    sobek.Value       `js:"data"`
+	Words   sobek.ArrayBuffer `js:"words"`
+	Variant string            `js:"variant"`
+}
+
+// bech32Parts are the parts of a decoded Bech32 string. data is nil when the words
+// option is set.
+type bech32Parts struct {
+	hrp     string
+	variant string
+	words   []byte
+	data    []byte
+}
+
+// maxLength resolves the MaxLength option.
+func (o Bech32Options) maxLength() (int, error) {
+	if o.MaxLength < 0 {
+		return 0, fmt.Errorf("%s: maxLength must not be negative, got %d", ErrInvalidOption, o.MaxLength)
+	}
+	if o.MaxLength == 0 {
+		return bech32DefaultMaxLength, nil
+	}
+	return o.MaxLength, nil
+}
+
+// bech32Polymod computes the BCH checksum of 5-bit values.
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range generator {
+			if (top>>i)&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand spreads the human-readable part over 5-bit values for the checksum.
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// validateBech32HRP checks that hrp is 1 to 83 printable ASCII characters.
+func validateBech32HRP(hrp string) error {
+	if len(hrp) < 1 || len(hrp) > 83 {
+		return fmt.Errorf("%s: human-readable part must have between 1 and 83 characters, got %d", ErrInvalidBech32, len(hrp))
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return fmt.Errorf("%s %q at offset %d", ErrBech32Character, hrp[i], i)
+		}
+	}
+	return nil
+}
+
+// bech32ToLower lower-cases the ASCII letters of s and reports whether it mixed upper
+// and lower case. Other bytes are kept as they are so they can be reported as invalid.
+func bech32ToLower(s string) (string, bool) {
+	var lower, upper bool
+	out := []byte(s)
+	for i, c := range out {
+		switch {
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+			out[i] = c + 'a' - 'A'
+		}
+	}
+	return string(out), lower && upper
+}
+
+// convertBits regroups data from fromBits-bit to toBits-bit values, most significant
+// bit first. With pad set, a final partial group is padded with zero bits; otherwise
+// the leftover bits must be fewer than fromBits and all zero.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+	maxValue := uint(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for i, b := range data {
+		if uint(b)>>fromBits != 0 {
+			return nil, fmt.Errorf("value %d at offset %d does not fit in %d bits", b, i, fromBits)
+		}
+		acc = acc<<fromBits | uint(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxValue))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
+
+// encode writes data as a Bech32 or Bech32m string with the human-readable part hrp.
+func (o Bech32Options) encode(hrp string, data []byte) (string, error) {
+	variant := strings.ToLower(o.Variant)
+	if variant == "" {
+		variant = Bech32
+	}
+	constant, ok := bech32Constants[variant]
+	if !ok {
+		return "", fmt.Errorf("%s: variant must be %q or %q, got %q", ErrInvalidOption, Bech32, Bech32m, o.Variant)
+	}
+	maxLength, err := o.maxLength()
+	if err != nil {
+		return "", err
+	}
+	if err := validateBech32HRP(hrp); err != nil {
+		return "", err
+	}
+	hrp, mixed := bech32ToLower(hrp)
+	if mixed {
+		return "", fmt.Errorf("%s: mixed-case human-readable part", ErrInvalidBech32)
+	}
+
+	words := data
+	if !o.Words {
+		words, _ = convertBits(data, 8, 5, true)
+	}
+	if length := len(hrp) + 1 + len(words) + bech32ChecksumLength; length > maxLength {
+		return "", fmt.Errorf("%s: length %d exceeds the limit of %d", ErrInvalidBech32, length, maxLength)
+	}
+
+	values := append(bech32HRPExpand(hrp), words...)
+	values = append(values, make([]byte, bech32ChecksumLength)...)
+	checksum := bech32Polymod(values) ^ constant
+
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(words) + bech32ChecksumLength)
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for i, w := range words {
+		if w >= 32 {
+			return "", fmt.Errorf("%s: word %d at offset %d does not fit in 5 bits", ErrInvalidBech32, w, i)
+		}
+		sb.WriteByte(bech32Charset[w])
+	}
+	for i := 0; i < bech32ChecksumLength; i++ {
+		sb.WriteByte(bech32Charset[checksum>>(5*(5-i))&31])
+	}
+	return sb.String(), nil
+}
+
+// decode splits a Bech32 or Bech32m string into its parts and verifies its checksum.
+func (o Bech32Options) decode(encoded string) (*bech32Parts, error) {
+	variant := strings.ToLower(o.Variant)
+	if _, ok := bech32Constants[variant]; !ok && variant != "" {
+		return nil, fmt.Errorf("%s: variant must be %q or %q, got %q", ErrInvalidOption, Bech32, Bech32m, o.Variant)
+	}
+	maxLength, err := o.maxLength()
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) > maxLength {
+		return nil, fmt.Errorf("%s: length %d exceeds the limit of %d", ErrInvalidBech32, len(encoded), maxLength)
+	}
+	encoded, mixed := bech32ToLower(encoded)
+	if mixed {
+		return nil, fmt.Errorf("%s: mixed case", ErrInvalidBech32)
+	}
+
+	sep := strings.LastIndexByte(encoded, '1')
+	if sep < 0 {
+		return nil, fmt.Errorf("%s: missing separator '1'", ErrInvalidBech32)
+	}
+	hrp := encoded[:sep]
+	if err := validateBech32HRP(hrp); err != nil {
+		return nil, err
+	}
+	if len(encoded)-sep-1 < bech32ChecksumLength {
+		return nil, fmt.Errorf("%s: data part is shorter than the %d character checksum", ErrInvalidBech32, bech32ChecksumLength)
+	}
+
+	values := make([]byte, 0, len(encoded)-sep-1)
+	for i := sep + 1; i < len(encoded); i++ {
+		v := strings.IndexByte(bech32Charset, encoded[i])
+		if v < 0 {
+			return nil, fmt.Errorf("%s %q at offset %d", ErrBech32Character, encoded[i], i)
+		}
+		values = append(values, byte(v))
+	}
+
+	polymod := bech32Polymod(append(bech32HRPExpand(hrp), values...))
+	switch {
+	case polymod == bech32Constants[Bech32] && variant != Bech32m:
+		variant = Bech32
+	case polymod == bech32Constants[Bech32m] && variant != Bech32:
+		variant = Bech32m
+	default:
+		return nil, errors.New(ErrBech32Checksum)
+	}
+
+	parts := &bech32Parts{hrp: hrp, variant: variant, words: values[:len(values)-bech32ChecksumLength]}
+	if !o.Words {
+		if parts.data, err = convertBits(parts.words, 5, 8, false); err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrInvalidBech32, err)
+		}
+	}
+	return parts, nil
+}
+
+// EncodeBech32 encodes the bytes of an ArrayBuffer, TypedArray or DataView as a Bech32 or
+// Bech32m string with the given human-readable part. The bytes are regrouped into 5-bit
+// words unless the words option is set.
+func (TextEncoding) EncodeBech32(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	hrp, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	data, err := bytesFromValue(rt, call.Argument(1))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o Bech32Options
+	if err := exportOptions(rt, call.Argument(2), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encode(hrp, data)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encoded)
+}
+
+// DecodeBech32 decodes a Bech32 or Bech32m string into its human-readable part and its
+// words and data as ArrayBuffers. Characters outside the Bech32 alphabet and checksum
+// mismatches are reported with distinct errors.
+func (TextEncoding) DecodeBech32(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	var o Bech32Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	parts, err := o.decode(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	result := &Bech32Result{HRP: parts.hrp, Words: rt.NewArrayBuffer(parts.words), Variant: parts.variant}
+	if parts.data != nil {
+		result.Data = rt.ToValue(rt.NewArrayBuffer(parts.data))
+	}
+	return rt.ToValue(result)
+}
+
+// ConvertBits regroups the values in an ArrayBuffer, TypedArray or DataView from
+// fromBits bits into values of toBits bits, as needed to build SegWit programs, and
+// returns them as an ArrayBuffer. Both sizes are between 1 and 8. With pad set, a final
+// partial group is padded with zero bits; otherwise leftover bits must be zero padding.
+func (TextEncoding) ConvertBits(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	fromBits, toBits := call.Argument(1).ToInteger(), call.Argument(2).ToInteger()
+	if fromBits < 1 || fromBits > 8 || toBits < 1 || toBits > 8 {
+		common.Throw(rt, fmt.Errorf("%s: bit sizes must be between 1 and 8, got %d and %d", ErrInvalidOption, fromBits, toBits))
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	out, err := convertBits(data, uint(fromBits), uint(toBits), call.Argument(3).ToBoolean())
+	if err != nil {
+		common.Throw(rt, fmt.Errorf("%s: %w", ErrConvertBits, err))
+	}
+	return rt.ToValue(rt.NewArrayBuffer(out))
+}
@@ -0,0 +1,188 @@
+package text_encoding
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBech32Valid(t *testing.T) {
+	// Test vectors from BIP 173 and BIP 350.
+	tests := []struct {
+		input   string
+		hrp     string
+		variant string
+	}{
+		{input: "A12UEL5L", hrp: "a", variant: Bech32},
+		{input: "a12uel5l", hrp: "a", variant: Bech32},
+		{input: "an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs", hrp: "an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio", variant: Bech32},
+		{input: "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", hrp: "abcdef", variant: Bech32},
+		{input: "split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", hrp: "split", variant: Bech32},
+		{input: "?1ezyfcl", hrp: "?", variant: Bech32},
+		{input: "A1LQFN3A", hrp: "a", variant: Bech32m},
+		{input: "abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", hrp: "abcdef", variant: Bech32m},
+		{input: "?1v759aa", hrp: "?", variant: Bech32m},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			parts, err := Bech32Options{Words: true}.decode(tt.input)
+			if err != nil {
+				t.Fatalf("decode() unexpected error: %v", err)
+			}
+			if parts.hrp != tt.hrp || parts.variant != tt.variant || parts.data != nil {
+				t.Errorf("decode() = %+v, want %q, %q and no data", parts, tt.hrp, tt.variant)
+			}
+
+			encoded, err := Bech32Options{Variant: parts.variant, Words: true}.encode(parts.hrp, parts.words)
+			if err != nil {
+				t.Fatalf("encode() unexpected error: %v", err)
+			}
+			if encoded != strings.ToLower(tt.input) {
+				t.Errorf("encode() = %q, want %q", encoded, strings.ToLower(tt.input))
+			}
+		})
+	}
+}
+
+func TestDecodeBech32Invalid(t *testing.T) {
+	// Test vectors from BIP 173 and BIP 350.
+	tests := []struct {
+		name     string
+		input    string
+		opts     Bech32Options
+		expected string
+	}{
+		{name: "hrp character out of range", input: "\x201nwldj5", expected: ErrBech32Character},
+		{name: "overall max length exceeded", input: "an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx", expected: ErrInvalidBech32},
+		{name: "no separator", input: "pzry9x0s0muk", expected: ErrInvalidBech32},
+		{name: "empty hrp", input: "1pzry9x0s0muk", expected: ErrInvalidBech32},
+		{name: "invalid data character", input: "x1b4n0q5v", expected: ErrBech32Character + " 'b' at offset 2"},
+		{name: "too short checksum", input: "li1dgmt3", expected: ErrInvalidBech32},
+		{name: "invalid character in checksum", input: "de1lg7wt\xff", expected: ErrBech32Character},
+		{name: "checksum calculated with upper case hrp", input: "A1G7SGD8", expected: ErrBech32Checksum},
+		{name: "mixed case", input: "a12UEL5L", expected: ErrInvalidBech32},
+		{name: "bech32m checksum as bech32", input: "A1LQFN3A", opts: Bech32Options{Variant: Bech32}, expected: ErrBech32Checksum},
+		{name: "bech32 checksum as bech32m", input: "A12UEL5L", opts: Bech32Options{Variant: Bech32m}, expected: ErrBech32Checksum},
+		{name: "corrupted checksum", input: "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxx", expected: ErrBech32Checksum},
+		{name: "unknown variant", input: "A12UEL5L", opts: Bech32Options{Variant: "bech33"}, expected: ErrInvalidOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.opts.decode(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("decode(%q) error = %v, want %q", tt.input, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBech32Bytes(t *testing.T) {
+	var o Bech32Options
+	data := []byte{0x00, 0x14, 0x75, 0x1E, 0x76, 0xE8, 0x19, 0x91, 0x96, 0xD4}
+
+	for _, variant := range []string{Bech32, Bech32m} {
+		encoded, err := Bech32Options{Variant: variant}.encode("test", data)
+		if err != nil {
+			t.Fatalf("encode() unexpected error: %v", err)
+		}
+		parts, err := o.decode(strings.ToUpper(encoded))
+		if err != nil {
+			t.Fatalf("decode(%q) unexpected error: %v", encoded, err)
+		}
+		if parts.hrp != "test" || parts.variant != variant || !bytes.Equal(parts.data, data) {
+			t.Errorf("decode(%q) = %+v, want test, %s, % X", encoded, parts, variant, data)
+		}
+	}
+
+	if _, err := o.encode("test", make([]byte, 60)); err == nil || !strings.Contains(err.Error(), "exceeds the limit") {
+		t.Errorf("encode() error = %v, want length limit", err)
+	}
+	long := Bech32Options{MaxLength: 1023}
+	encoded, err := long.encode("lnbc", make([]byte, 60))
+	if err != nil {
+		t.Fatalf("encode() unexpected error: %v", err)
+	}
+	if _, err := long.decode(encoded); err != nil {
+		t.Errorf("decode() unexpected error: %v", err)
+	}
+	if _, err := o.encode("Test", data); err == nil {
+		t.Error("encode() expected error for mixed-case hrp")
+	}
+	if _, err := (Bech32Options{Words: true}).encode("test", []byte{32}); err == nil {
+		t.Error("encode() expected error for a word larger than 5 bits")
+	}
+}
+
+func TestConvertBits(t *testing.T) {
+	words, err := convertBits([]byte{0xFF}, 8, 5, true)
+	if err != nil {
+		t.Fatalf("convertBits() unexpected error: %v", err)
+	}
+	if !bytes.Equal(words, []byte{31, 28}) {
+		t.Errorf("convertBits() = %v, want [31 28]", words)
+	}
+	data, err := convertBits(words, 5, 8, false)
+	if err != nil {
+		t.Fatalf("convertBits() unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, []byte{0xFF}) {
+		t.Errorf("convertBits() = % X, want FF", data)
+	}
+
+	if _, err := convertBits([]byte{31, 29}, 5, 8, false); err == nil {
+		t.Error("convertBits() expected error for non-zero padding")
+	}
+	if _, err := convertBits([]byte{32}, 5, 8, true); err == nil {
+		t.Error("convertBits() expected error for a value larger than fromBits")
+	}
+}
+
+func TestBech32JS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const program = new Uint8Array([0x75, 0x1E, 0x76, 0xE8, 0x19, 0x91, 0x96, 0xD4, 0x54, 0x94, 0x1C, 0x45, 0xD1, 0xB3, 0xA3, 0x23, 0xF1, 0x43, 0x3B, 0xD6]);
+		const words = new Uint8Array(encoding.convertBits(program, 8, 5, true));
+		// The P2WPKH example address from BIP 173.
+		const address = encoding.encodeBech32('bc', new Uint8Array([0, ...words]), { words: true });
+		const decoded = encoding.decodeBech32(address, { words: true });
+		const back = encoding.convertBits(new Uint8Array(decoded.words).subarray(1), 5, 8, false);
+		const bytes = encoding.decodeBech32(encoding.encodeBech32('test', program));
+		return [
+			address,
+			decoded.hrp,
+			decoded.variant,
+			decoded.words instanceof ArrayBuffer,
+			new Uint8Array(decoded.words)[0],
+			decoded.data,
+			back instanceof ArrayBuffer,
+			back.byteLength,
+			bytes.data instanceof ArrayBuffer,
+			bytes.data.byteLength,
+		].join('|');
+	})()`).String()
+	expected := "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4|bc|bech32|true|0||true|20|true|20"
+	if result != expected {
+		t.Errorf("bech32 = %q, want %q", result, expected)
+	}
+
+	_, err := rt.RunString(`encoding.decodeBech32('bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5')`)
+	if err == nil || !strings.Contains(err.Error(), ErrBech32Checksum) {
+		t.Errorf("decodeBech32() error = %v, want %q", err, ErrBech32Checksum)
+	}
+
+	throwing := map[string]string{
+		`encoding.encodeBech32('bc', 'abc')`:                     ErrNotBufferSource,
+		`encoding.encodeBech32('bc', [0, 1], { words: true })`:   ErrNotBufferSource,
+		`encoding.convertBits([255], 8, 5, true)`:                ErrNotBufferSource,
+		`encoding.convertBits(new Uint8Array([1]), 0, 8, true)`:  ErrInvalidOption,
+		`encoding.convertBits(new Uint8Array([32]), 5, 8, true)`: ErrConvertBits,
+	}
+	for script, expected := range throwing {
+		if _, err := rt.RunString(script); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s error = %v, want %q", script, err, expected)
+		}
+	}
+}
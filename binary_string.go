@@ -0,0 +1,62 @@
+package text_encoding
+
+import (
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// ErrInvalidBinaryString is returned when a binary string has a character code above 0xFF.
+const ErrInvalidBinaryString = "invalid binary string"
+
+// binaryStringBytes converts a JavaScript binary string, in which each character code
+// is one byte, to bytes. The error gives the index of the first character above 0xFF.
+func binaryStringBytes(v sobek.Value) ([]byte, error) {
+	text, err := stringFromValue(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateInputSize(len(text)); err != nil {
+		return nil, err
+	}
+	return latin1Bytes(text, func(_ []byte, r rune, index int) ([]byte, error) {
+		return nil, fmt.Errorf("%s: character code 0x%X at index %d exceeds 0xFF", ErrInvalidBinaryString, r, index)
+	})
+}
+
+// BinaryStringToArrayBuffer converts a binary string, such as the output of atob or
+// crypto-js, to an ArrayBuffer holding one byte per character.
+func (TextEncoding) BinaryStringToArrayBuffer(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := binaryStringBytes(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(data))
+}
+
+// BinaryStringToUint8Array converts a binary string to a Uint8Array.
+func (TextEncoding) BinaryStringToUint8Array(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := binaryStringBytes(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	arr, err := newUint8Array(rt, data)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return arr
+}
+
+// ArrayBufferToBinaryString converts the bytes of an ArrayBuffer, TypedArray or DataView
+// to a binary string with one character per byte. Only the region covered by a view is used.
+func (TextEncoding) ArrayBufferToBinaryString(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(latin1String(data))
+}
@@ -0,0 +1,57 @@
+package text_encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBinaryStringJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		let binary = '';
+		for (let i = 0; i < 256; i++) binary += String.fromCharCode(i);
+		const buffer = encoding.binaryStringToArrayBuffer(binary);
+		const bytes = encoding.binaryStringToUint8Array(binary);
+		const view = new DataView(buffer, 250, 4);
+		const back = encoding.arrayBufferToBinaryString(buffer);
+		return [
+			buffer instanceof ArrayBuffer,
+			buffer.byteLength,
+			bytes instanceof Uint8Array,
+			bytes[0xE6],
+			back === binary,
+			encoding.arrayBufferToBinaryString(bytes.subarray(0x41, 0x44)),
+			Array.from(encoding.arrayBufferToBinaryString(view)).map((c) => c.charCodeAt(0)).join(','),
+			encoding.binaryStringToArrayBuffer('').byteLength,
+		].join('|');
+	})()`).String()
+	expected := "true|256|true|230|true|ABC|250,251,252,253|0"
+	if result != expected {
+		t.Errorf("binary strings = %q, want %q", result, expected)
+	}
+}
+
+func TestBinaryStringErrors(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	tests := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{name: "above 0xFF", script: `encoding.binaryStringToArrayBuffer('abĀ')`, expected: ErrInvalidBinaryString + ": character code 0x100 at index 2 exceeds 0xFF"},
+		{name: "emoji", script: `encoding.binaryStringToUint8Array('\xFF🚀')`, expected: "character code 0x1F680 at index 1"},
+		{name: "null", script: `encoding.binaryStringToArrayBuffer(null)`, expected: ErrNilInput},
+		{name: "not a buffer", script: `encoding.arrayBufferToBinaryString('abc')`, expected: ErrNotBufferSource},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := rt.RunString(tt.script)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("%s error = %v, want %q", tt.script, err, tt.expected)
+			}
+		})
+	}
+}
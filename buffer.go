@@ -0,0 +1,871 @@
+package text_encoding
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// Error messages for Buffer
+const (
+	ErrUnknownEncoding  = "unknown encoding"
+	ErrBufferOutOfRange = "buffer access out of range"
+	ErrNotBufferInput   = "value cannot be converted to a Buffer"
+)
+
+// Encodings accepted by Buffer methods, named as in Node.js. Names are case-insensitive
+// and "utf-8", "binary", "ucs2", "ucs-2" and "utf-16le" are accepted as aliases.
+const (
+	BufferUTF8      = "utf8"
+	BufferHex       = "hex"
+	BufferBase64    = "base64"
+	BufferBase64URL = "base64url"
+	BufferLatin1    = "latin1"
+	BufferASCII     = "ascii"
+	BufferUTF16LE   = "utf16le"
+)
+
+var bufferEncodings = map[string]string{
+	"utf8":      BufferUTF8,
+	"utf-8":     BufferUTF8,
+	"hex":       BufferHex,
+	"base64":    BufferBase64,
+	"base64url": BufferBase64URL,
+	"latin1":    BufferLatin1,
+	"binary":    BufferLatin1,
+	"ascii":     BufferASCII,
+	"utf16le":   BufferUTF16LE,
+	"utf-16le":  BufferUTF16LE,
+	"ucs2":      BufferUTF16LE,
+	"ucs-2":     BufferUTF16LE,
+}
+
+// bufferClass is the Buffer class of one runtime. Its static methods mirror those of the
+// Node.js Buffer class. The buffers they return are Uint8Arrays whose prototype adds the
+// Node.js Buffer methods, so indexing and length work as usual.
+type bufferClass struct {
+	rt          *sobek.Runtime
+	constructor *sobek.Object
+	prototype   *sobek.Object
+}
+
+// bufferEncoding resolves an optional encoding argument, which defaults to UTF-8.
+func bufferEncoding(v sobek.Value) (string, error) {
+	if common.IsNullish(v) {
+		return BufferUTF8, nil
+	}
+	if enc, ok := bufferEncodings[strings.ToLower(v.String())]; ok {
+		return enc, nil
+	}
+	return "", fmt.Errorf("%s: %q", ErrUnknownEncoding, v.String())
+}
+
+// encodeBufferString converts a string to bytes in a Buffer encoding. Unlike Node.js,
+// invalid hex or base64 and characters above 0xFF in latin1 or ascii are errors
+// rather than being truncated or dropped.
+func encodeBufferString(v sobek.Value, enc string) ([]byte, error) {
+	switch enc {
+	case BufferHex:
+		return HexOptions{}.decode(v.String())
+	case BufferBase64, BufferBase64URL:
+		// Like Node.js, accept either alphabet, with or without padding, for both encodings.
+		encoded := strings.NewReplacer("-", "+", "_", "/").Replace(v.String())
+		return Base64Options{Padding: PaddingOptional, Lenient: true}.decode(encoded)
+	case BufferLatin1, BufferASCII:
+		return binaryStringBytes(v)
+	case BufferUTF16LE:
+		// Encode code units, so lone surrogates survive a round trip as in Node.js.
+		s, ok := v.ToString().(sobek.String)
+		if !ok {
+			return []byte(v.String()), nil
+		}
+		out := make([]byte, 2*s.Length())
+		for i := 0; i < s.Length(); i++ {
+			binary.LittleEndian.PutUint16(out[2*i:], s.CharAt(i))
+		}
+		return out, nil
+	default:
+		return []byte(v.String()), nil
+	}
+}
+
+// decodeBufferString converts bytes to a string in a Buffer encoding. Invalid UTF-8 is
+// replaced with U+FFFD, ascii ignores the high bit of each byte and a trailing odd
+// byte is ignored by utf16le, as in Node.js.
+func decodeBufferString(rt *sobek.Runtime, data []byte, enc string) sobek.Value {
+	switch enc {
+	case BufferHex:
+		return rt.ToValue(hex.EncodeToString(data))
+	case BufferBase64:
+		return rt.ToValue(base64.StdEncoding.EncodeToString(data))
+	case BufferBase64URL:
+		return rt.ToValue(base64.RawURLEncoding.EncodeToString(data))
+	case BufferLatin1:
+		return rt.ToValue(latin1String(data))
+	case BufferASCII:
+		out := make([]byte, len(data))
+		for i, b := range data {
+			out[i] = b & 0x7F
+		}
+		return rt.ToValue(string(out))
+	case BufferUTF16LE:
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			units[i] = binary.LittleEndian.Uint16(data[2*i:])
+		}
+		return sobek.StringFromUTF16(units)
+	default:
+		text, _, _, _ := decodeUTF8Chunk(data, false, true)
+		return rt.ToValue(text)
+	}
+}
+
+// newBufferClass creates the Buffer class of a runtime. As in Node.js, the constructor
+// inherits from Uint8Array, so Buffer[Symbol.species] is Buffer and TypedArray methods
+// that create arrays, such as map and filter, return Buffers.
+func newBufferClass(rt *sobek.Runtime) *bufferClass {
+	c := &bufferClass{rt: rt}
+	c.constructor = rt.ToValue(c.construct).ToObject(rt)
+	c.prototype = c.constructor.Get("prototype").ToObject(rt)
+
+	uint8Array := rt.Get("Uint8Array").ToObject(rt)
+	if err := c.constructor.SetPrototype(uint8Array); err != nil {
+		common.Throw(rt, err)
+	}
+	if err := c.prototype.SetPrototype(uint8Array.Get("prototype").ToObject(rt)); err != nil {
+		common.Throw(rt, err)
+	}
+	for name, method := range c.statics() {
+		if err := c.constructor.DefineDataProperty(name, rt.ToValue(method), sobek.FLAG_TRUE, sobek.FLAG_TRUE, sobek.FLAG_FALSE); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	for name, method := range (bufferMethods{c}).all() {
+		if err := c.prototype.DefineDataProperty(name, rt.ToValue(method), sobek.FLAG_TRUE, sobek.FLAG_TRUE, sobek.FLAG_FALSE); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	return c
+}
+
+// statics returns the static methods of the class by JavaScript name.
+func (c *bufferClass) statics() map[string]func(sobek.FunctionCall) sobek.Value {
+	return map[string]func(sobek.FunctionCall) sobek.Value{
+		"from":        c.from,
+		"alloc":       c.alloc,
+		"allocUnsafe": c.allocUnsafe,
+		"concat":      c.concat,
+		"compare":     c.compare,
+		"isBuffer":    c.isBuffer,
+		"isEncoding":  c.isEncoding,
+		"byteLength":  c.byteLength,
+	}
+}
+
+// construct implements new Buffer(size) and new Buffer(value), the deprecated Node.js
+// forms of Buffer.allocUnsafe and Buffer.from. TypedArray methods call it through
+// Symbol.species with a length, or with an ArrayBuffer, an offset and a length.
+func (c *bufferClass) construct(call sobek.ConstructorCall) *sobek.Object {
+	args := sobek.FunctionCall{This: c.constructor, Arguments: call.Arguments}
+	switch call.Argument(0).Export().(type) {
+	case int64, float64:
+		return c.allocUnsafe(args).ToObject(c.rt)
+	}
+	return c.from(args).ToObject(c.rt)
+}
+
+// newBuffer wraps data in a new Buffer without copying it.
+func (c *bufferClass) newBuffer(data []byte) *sobek.Object {
+	return c.newBufferView(c.rt.ToValue(c.rt.NewArrayBuffer(data)), 0, len(data))
+}
+
+// newBufferView returns a Buffer over length bytes of an ArrayBuffer from offset.
+func (c *bufferClass) newBufferView(arrayBuffer sobek.Value, offset, length int) *sobek.Object {
+	obj, err := c.rt.New(c.rt.Get("Uint8Array"), arrayBuffer, c.rt.ToValue(offset), c.rt.ToValue(length))
+	if err != nil {
+		common.Throw(c.rt, err)
+	}
+	if err := obj.SetPrototype(c.prototype); err != nil {
+		common.Throw(c.rt, err)
+	}
+	return obj
+}
+
+// valueBytes converts the value argument of fill, indexOf and Buffer.alloc to bytes:
+// a string in the given encoding, a number as a single byte, or the bytes of a buffer.
+func valueBytes(rt *sobek.Runtime, v sobek.Value, enc string) ([]byte, error) {
+	switch v.Export().(type) {
+	case string:
+		return encodeBufferString(v, enc)
+	case int64, float64:
+		return []byte{byte(v.ToInteger())}, nil
+	}
+	return bytesFromValue(rt, v)
+}
+
+// fillBytes repeats pattern over dst.
+func fillBytes(dst, pattern []byte) error {
+	if len(pattern) == 0 {
+		if len(dst) > 0 {
+			return fmt.Errorf("%s: fill value is empty", ErrInvalidOption)
+		}
+		return nil
+	}
+	for i := 0; i < len(dst); i += len(pattern) {
+		copy(dst[i:], pattern)
+	}
+	return nil
+}
+
+// clampIndex resolves a start or end argument as Node.js does: undefined means def,
+// negative values count from the end and the result is clamped to [0, length].
+func clampIndex(v sobek.Value, length, def int) int {
+	if sobek.IsUndefined(v) {
+		return def
+	}
+	i := v.ToInteger()
+	if i < 0 {
+		i += int64(length)
+	}
+	return int(max(0, min(i, int64(length))))
+}
+
+// bufferFromValue converts the first argument of Buffer.from to bytes. ArrayBuffers are
+// shared rather than copied and are handled by the caller.
+func bufferFromValue(rt *sobek.Runtime, v sobek.Value, encoding sobek.Value) ([]byte, error) {
+	if common.IsNullish(v) {
+		return nil, errors.New(ErrNotBufferInput)
+	}
+	if _, ok := v.Export().(string); ok {
+		enc, err := bufferEncoding(encoding)
+		if err != nil {
+			return nil, err
+		}
+		return encodeBufferString(v, enc)
+	}
+	obj, ok := v.(*sobek.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s: got %s", ErrNotBufferInput, v.String())
+	}
+	if data, ok := v.Export().([]byte); ok {
+		return bytes.Clone(data), nil
+	}
+	if kind, data := obj.Get("type"), obj.Get("data"); kind != nil && kind.String() == "Buffer" && data != nil {
+		// The form written by Buffer.prototype.toJSON.
+		obj = data.ToObject(rt)
+	}
+	lengthValue := obj.Get("length")
+	if lengthValue == nil || sobek.IsUndefined(lengthValue) {
+		return nil, fmt.Errorf("%s: expected a string, Buffer, ArrayBuffer, array or array-like object", ErrNotBufferInput)
+	}
+	length := lengthValue.ToInteger()
+	if length < 0 || length > MaxInputSize {
+		return nil, fmt.Errorf("%s: invalid length %d", ErrNotBufferInput, length)
+	}
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = byte(obj.Get(fmt.Sprint(i)).ToInteger())
+	}
+	return out, nil
+}
+
+// from creates a Buffer from a string in an encoding (UTF-8 by default), an array of
+// bytes, a TypedArray or another Buffer, which are copied, or an ArrayBuffer, which
+// is shared, optionally from a byte offset and for a length.
+func (c *bufferClass) from(call sobek.FunctionCall) sobek.Value {
+	value := call.Argument(0)
+	if ab, ok := value.Export().(sobek.ArrayBuffer); ok {
+		size := len(ab.Bytes())
+		offset := 0
+		if !sobek.IsUndefined(call.Argument(1)) {
+			offset = int(call.Argument(1).ToInteger())
+		}
+		if offset < 0 || offset > size {
+			common.Throw(c.rt, fmt.Errorf("%s: offset %d is outside the ArrayBuffer length %d", ErrBufferOutOfRange, offset, size))
+		}
+		length := size - offset
+		if !sobek.IsUndefined(call.Argument(2)) {
+			length = int(call.Argument(2).ToInteger())
+		}
+		if length < 0 || offset+length > size {
+			common.Throw(c.rt, fmt.Errorf("%s: %d bytes at offset %d exceed the ArrayBuffer length %d", ErrBufferOutOfRange, length, offset, size))
+		}
+		return c.newBufferView(value, offset, length)
+	}
+	data, err := bufferFromValue(c.rt, value, call.Argument(1))
+	if err != nil {
+		common.Throw(c.rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(c.rt, err)
+	}
+	return c.newBuffer(data)
+}
+
+// allocSize validates the size argument of Buffer.alloc and Buffer.allocUnsafe.
+func allocSize(v sobek.Value) (int, error) {
+	size := v.ToFloat()
+	if size != math.Trunc(size) || size < 0 || size > MaxInputSize {
+		return 0, fmt.Errorf("%s: size must be an integer between 0 and %d, got %s", ErrInvalidOption, MaxInputSize, v.String())
+	}
+	return int(size), nil
+}
+
+// alloc creates a zero-filled Buffer of size bytes, or one filled with a number, a
+// string in an encoding or the bytes of another buffer.
+func (c *bufferClass) alloc(call sobek.FunctionCall) sobek.Value {
+	size, err := allocSize(call.Argument(0))
+	if err != nil {
+		common.Throw(c.rt, err)
+	}
+	data := make([]byte, size)
+	if fill := call.Argument(1); !common.IsNullish(fill) {
+		enc, err := bufferEncoding(call.Argument(2))
+		if err != nil {
+			common.Throw(c.rt, err)
+		}
+		pattern, err := valueBytes(c.rt, fill, enc)
+		if err != nil {
+			common.Throw(c.rt, err)
+		}
+		if err := fillBytes(data, pattern); err != nil {
+			common.Throw(c.rt, err)
+		}
+	}
+	return c.newBuffer(data)
+}
+
+// allocUnsafe creates a Buffer of size bytes. Unlike Node.js the memory is always zeroed.
+func (c *bufferClass) allocUnsafe(call sobek.FunctionCall) sobek.Value {
+	size, err := allocSize(call.Argument(0))
+	if err != nil {
+		common.Throw(c.rt, err)
+	}
+	return c.newBuffer(make([]byte, size))
+}
+
+// concat joins an array of Buffers or Uint8Arrays into a new Buffer. With totalLength
+// the result is truncated or zero-padded to that length.
+func (c *bufferClass) concat(call sobek.FunctionCall) sobek.Value {
+	var list []sobek.Value
+	if err := c.rt.ExportTo(call.Argument(0), &list); err != nil {
+		common.Throw(c.rt, fmt.Errorf("%s: list must be an array of buffers: %w", ErrInvalidOption, err))
+	}
+	var out []byte
+	for i, item := range list {
+		data, err := bytesFromValue(c.rt, item)
+		if err != nil {
+			common.Throw(c.rt, fmt.Errorf("%w: list item %d", err, i))
+		}
+		out = append(out, data...)
+		if err := validateInputSize(len(out)); err != nil {
+			common.Throw(c.rt, err)
+		}
+	}
+	if total := call.Argument(1); !sobek.IsUndefined(total) {
+		size, err := allocSize(total)
+		if err != nil {
+			common.Throw(c.rt, err)
+		}
+		out = append(out, make([]byte, max(0, size-len(out)))...)[:size]
+	}
+	if out == nil {
+		out = []byte{}
+	}
+	return c.newBuffer(out)
+}
+
+// compare compares two buffers byte by byte, returning -1, 0 or 1, so it can be
+// passed to Array.prototype.sort.
+func (c *bufferClass) compare(call sobek.FunctionCall) sobek.Value {
+	a, err := bytesFromValue(c.rt, call.Argument(0))
+	if err != nil {
+		common.Throw(c.rt, err)
+	}
+	b, err := bytesFromValue(c.rt, call.Argument(1))
+	if err != nil {
+		common.Throw(c.rt, err)
+	}
+	return c.rt.ToValue(bytes.Compare(a, b))
+}
+
+// isBuffer reports whether the value is a Buffer created by this module.
+func (c *bufferClass) isBuffer(call sobek.FunctionCall) sobek.Value {
+	obj, ok := call.Argument(0).(*sobek.Object)
+	return c.rt.ToValue(ok && obj.Prototype() == c.prototype)
+}
+
+// isEncoding reports whether the value names a supported encoding.
+func (c *bufferClass) isEncoding(call sobek.FunctionCall) sobek.Value {
+	arg := call.Argument(0)
+	_, isString := arg.Export().(string)
+	_, err := bufferEncoding(arg)
+	return c.rt.ToValue(isString && err == nil)
+}
+
+// byteLength returns the number of bytes of a string in an encoding, or of a buffer.
+func (c *bufferClass) byteLength(call sobek.FunctionCall) sobek.Value {
+	value := call.Argument(0)
+	if _, ok := value.Export().(string); !ok {
+		data, err := bytesFromValue(c.rt, value)
+		if err != nil {
+			common.Throw(c.rt, err)
+		}
+		return c.rt.ToValue(len(data))
+	}
+	enc, err := bufferEncoding(call.Argument(1))
+	if err != nil {
+		common.Throw(c.rt, err)
+	}
+	data, err := encodeBufferString(value, enc)
+	if err != nil {
+		common.Throw(c.rt, err)
+	}
+	return c.rt.ToValue(len(data))
+}
+
+// bufferMethods implements the methods of the Buffer prototype. Each method reads
+// the bytes of this, so writes change the memory shared with other views.
+type bufferMethods struct {
+	*bufferClass
+}
+
+// all returns the prototype methods by JavaScript name.
+func (m bufferMethods) all() map[string]func(sobek.FunctionCall) sobek.Value {
+	methods := map[string]func(sobek.FunctionCall) sobek.Value{
+		"toString":    m.toString,
+		"toJSON":      m.toJSON,
+		"equals":      m.equals,
+		"compare":     m.compare,
+		"slice":       m.subarray,
+		"subarray":    m.subarray,
+		"indexOf":     m.indexOf,
+		"lastIndexOf": m.lastIndexOf,
+		"includes":    m.includes,
+		"write":       m.write,
+		"copy":        m.copy,
+		"fill":        m.fill,
+	}
+	for _, a := range bufferAccessors {
+		methods["read"+a.name] = m.reader(a)
+		methods["write"+a.name] = m.writer(a)
+		if strings.Contains(a.name, "UInt") {
+			alias := strings.Replace(a.name, "UInt", "Uint", 1)
+			methods["read"+alias] = methods["read"+a.name]
+			methods["write"+alias] = methods["write"+a.name]
+		}
+	}
+	for _, name := range []string{"UIntBE", "UIntLE", "IntBE", "IntLE"} {
+		methods["read"+name] = m.readVariable(name)
+		methods["write"+name] = m.writeVariable(name)
+		if strings.HasPrefix(name, "UInt") {
+			methods["readUint"+name[4:]] = methods["read"+name]
+			methods["writeUint"+name[4:]] = methods["write"+name]
+		}
+	}
+	return methods
+}
+
+// this returns the bytes viewed by the this value of call.
+func (m bufferMethods) this(call sobek.FunctionCall) []byte {
+	data, err := bytesFromValue(m.rt, call.This)
+	if err != nil {
+		common.Throw(m.rt, err)
+	}
+	return data
+}
+
+// encoding resolves an encoding argument, throwing if it is unknown.
+func (m bufferMethods) encoding(v sobek.Value) string {
+	enc, err := bufferEncoding(v)
+	if err != nil {
+		common.Throw(m.rt, err)
+	}
+	return enc
+}
+
+// toString decodes the bytes from start to end in an encoding, UTF-8 by default.
+func (m bufferMethods) toString(call sobek.FunctionCall) sobek.Value {
+	data := m.this(call)
+	enc := m.encoding(call.Argument(0))
+	start := clampIndex(call.Argument(1), len(data), 0)
+	end := clampIndex(call.Argument(2), len(data), len(data))
+	if start >= end {
+		return m.rt.ToValue("")
+	}
+	return decodeBufferString(m.rt, data[start:end], enc)
+}
+
+// toJSON returns {type: 'Buffer', data: [...]}, which Buffer.from accepts.
+func (m bufferMethods) toJSON(call sobek.FunctionCall) sobek.Value {
+	data := m.this(call)
+	values := make([]any, len(data))
+	for i, b := range data {
+		values[i] = b
+	}
+	obj := m.rt.NewObject()
+	_ = obj.Set("type", "Buffer")
+	_ = obj.Set("data", m.rt.NewArray(values...))
+	return obj
+}
+
+// equals reports whether another buffer holds the same bytes.
+func (m bufferMethods) equals(call sobek.FunctionCall) sobek.Value {
+	other, err := bytesFromValue(m.rt, call.Argument(0))
+	if err != nil {
+		common.Throw(m.rt, err)
+	}
+	return m.rt.ToValue(bytes.Equal(m.this(call), other))
+}
+
+// compare compares with a target buffer, optionally restricted to
+// [targetStart, targetEnd) and [sourceStart, sourceEnd).
+func (m bufferMethods) compare(call sobek.FunctionCall) sobek.Value {
+	target, err := bytesFromValue(m.rt, call.Argument(0))
+	if err != nil {
+		common.Throw(m.rt, err)
+	}
+	source := m.this(call)
+	targetStart := clampIndex(call.Argument(1), len(target), 0)
+	targetEnd := max(targetStart, clampIndex(call.Argument(2), len(target), len(target)))
+	sourceStart := clampIndex(call.Argument(3), len(source), 0)
+	sourceEnd := max(sourceStart, clampIndex(call.Argument(4), len(source), len(source)))
+	return m.rt.ToValue(bytes.Compare(source[sourceStart:sourceEnd], target[targetStart:targetEnd]))
+}
+
+// subarray returns a Buffer sharing the memory from start to end. Node.js gives slice
+// the same behavior.
+func (m bufferMethods) subarray(call sobek.FunctionCall) sobek.Value {
+	data := m.this(call)
+	start := clampIndex(call.Argument(0), len(data), 0)
+	end := max(start, clampIndex(call.Argument(1), len(data), len(data)))
+	obj := call.This.ToObject(m.rt)
+	offset := int(obj.Get("byteOffset").ToInteger())
+	return m.newBufferView(obj.Get("buffer"), offset+start, end-start)
+}
+
+// search resolves the value, byteOffset and encoding arguments of indexOf and lastIndexOf.
+// A string byteOffset is taken as the encoding.
+func (m bufferMethods) search(call sobek.FunctionCall) (data, needle []byte, offset sobek.Value) {
+	data = m.this(call)
+	offset, encoding := call.Argument(1), call.Argument(2)
+	if _, ok := offset.Export().(string); ok {
+		offset, encoding = sobek.Undefined(), offset
+	}
+	needle, err := valueBytes(m.rt, call.Argument(0), m.encoding(encoding))
+	if err != nil {
+		common.Throw(m.rt, err)
+	}
+	return data, needle, offset
+}
+
+// indexOf returns the first offset of a string, byte or buffer at or after byteOffset, or -1.
+func (m bufferMethods) indexOf(call sobek.FunctionCall) sobek.Value {
+	data, needle, offset := m.search(call)
+	start := clampIndex(offset, len(data), 0)
+	if i := bytes.Index(data[start:], needle); i >= 0 {
+		return m.rt.ToValue(start + i)
+	}
+	return m.rt.ToValue(-1)
+}
+
+// lastIndexOf returns the last offset of a string, byte or buffer at or before byteOffset, or -1.
+func (m bufferMethods) lastIndexOf(call sobek.FunctionCall) sobek.Value {
+	data, needle, offset := m.search(call)
+	end := len(data)
+	if !sobek.IsUndefined(offset) {
+		start := offset.ToInteger()
+		if start < 0 {
+			start += int64(len(data))
+		}
+		if start < 0 {
+			return m.rt.ToValue(-1)
+		}
+		end = int(min(int64(len(data)), start+int64(len(needle))))
+	}
+	return m.rt.ToValue(bytes.LastIndex(data[:end], needle))
+}
+
+// includes reports whether indexOf finds the value.
+func (m bufferMethods) includes(call sobek.FunctionCall) sobek.Value {
+	return m.rt.ToValue(m.indexOf(call).ToInteger() >= 0)
+}
+
+// write encodes a string into the buffer at offset, writing at most length bytes, and
+// returns the number of bytes written. UTF-8 characters that do not fit are not split.
+// As in Node.js, the encoding may be given in place of offset or length.
+func (m bufferMethods) write(call sobek.FunctionCall) sobek.Value {
+	data := m.this(call)
+	args := call.Arguments[min(1, len(call.Arguments)):]
+	encoding := sobek.Undefined()
+	if n := len(args); n > 0 {
+		if _, ok := args[n-1].Export().(string); ok {
+			encoding, args = args[n-1], args[:n-1]
+		}
+	}
+	enc := m.encoding(encoding)
+	src, err := encodeBufferString(call.Argument(0), enc)
+	if err != nil {
+		common.Throw(m.rt, err)
+	}
+
+	offset := 0
+	if len(args) > 0 && !sobek.IsUndefined(args[0]) {
+		offset = int(args[0].ToInteger())
+	}
+	if offset < 0 || offset > len(data) {
+		common.Throw(m.rt, fmt.Errorf("%s: offset must be between 0 and %d, got %d", ErrBufferOutOfRange, len(data), offset))
+	}
+	limit := len(data) - offset
+	if len(args) > 1 && !sobek.IsUndefined(args[1]) {
+		limit = int(max(0, min(args[1].ToInteger(), int64(limit))))
+	}
+	if len(src) > limit {
+		src = src[:limit]
+		if enc == BufferUTF8 {
+			for len(src) > 0 && !utf8.Valid(src) {
+				src = src[:len(src)-1]
+			}
+		}
+	}
+	return m.rt.ToValue(copy(data[offset:], src))
+}
+
+// copy copies bytes from sourceStart to sourceEnd into a target buffer at targetStart
+// and returns the number of bytes copied. Overlapping regions are handled.
+func (m bufferMethods) copy(call sobek.FunctionCall) sobek.Value {
+	target, err := bytesFromValue(m.rt, call.Argument(0))
+	if err != nil {
+		common.Throw(m.rt, err)
+	}
+	source := m.this(call)
+	targetStart := clampIndex(call.Argument(1), len(target), 0)
+	sourceStart := clampIndex(call.Argument(2), len(source), 0)
+	sourceEnd := max(sourceStart, clampIndex(call.Argument(3), len(source), len(source)))
+	return m.rt.ToValue(copy(target[targetStart:], source[sourceStart:sourceEnd]))
+}
+
+// fill repeats a number, a string in an encoding or a buffer from offset to end and
+// returns this. As in Node.js, the encoding may be given in place of offset or end.
+func (m bufferMethods) fill(call sobek.FunctionCall) sobek.Value {
+	data := m.this(call)
+	args := call.Arguments[min(1, len(call.Arguments)):]
+	encoding := sobek.Undefined()
+	if n := len(args); n > 0 {
+		if _, ok := args[n-1].Export().(string); ok {
+			encoding, args = args[n-1], args[:n-1]
+		}
+	}
+	start, end := 0, len(data)
+	if len(args) > 0 {
+		start = clampIndex(args[0], len(data), 0)
+	}
+	if len(args) > 1 {
+		end = max(start, clampIndex(args[1], len(data), len(data)))
+	}
+	pattern, err := valueBytes(m.rt, call.Argument(0), m.encoding(encoding))
+	if err != nil {
+		common.Throw(m.rt, err)
+	}
+	if err := fillBytes(data[start:end], pattern); err != nil {
+		common.Throw(m.rt, err)
+	}
+	return call.This
+}
+
+// bufferAccessor describes a fixed-size read and write method pair such as
+// readUInt32BE and writeUInt32BE.
+type bufferAccessor struct {
+	name   string
+	size   int
+	signed bool
+	little bool
+	kind   byte // 'i' for integers, 'f' for floats and 'b' for BigInts
+}
+
+var bufferAccessors = []bufferAccessor{
+	{name: "UInt8", size: 1, kind: 'i'},
+	{name: "Int8", size: 1, signed: true, kind: 'i'},
+	{name: "UInt16LE", size: 2, little: true, kind: 'i'},
+	{name: "UInt16BE", size: 2, kind: 'i'},
+	{name: "Int16LE", size: 2, signed: true, little: true, kind: 'i'},
+	{name: "Int16BE", size: 2, signed: true, kind: 'i'},
+	{name: "UInt32LE", size: 4, little: true, kind: 'i'},
+	{name: "UInt32BE", size: 4, kind: 'i'},
+	{name: "Int32LE", size: 4, signed: true, little: true, kind: 'i'},
+	{name: "Int32BE", size: 4, signed: true, kind: 'i'},
+	{name: "BigUInt64LE", size: 8, little: true, kind: 'b'},
+	{name: "BigUInt64BE", size: 8, kind: 'b'},
+	{name: "BigInt64LE", size: 8, signed: true, little: true, kind: 'b'},
+	{name: "BigInt64BE", size: 8, signed: true, kind: 'b'},
+	{name: "FloatLE", size: 4, little: true, kind: 'f'},
+	{name: "FloatBE", size: 4, kind: 'f'},
+	{name: "DoubleLE", size: 8, little: true, kind: 'f'},
+	{name: "DoubleBE", size: 8, kind: 'f'},
+}
+
+// readUint reads an unsigned integer from all of b.
+func readUint(b []byte, little bool) uint64 {
+	var v uint64
+	for i := range b {
+		if little {
+			v = v<<8 | uint64(b[len(b)-1-i])
+		} else {
+			v = v<<8 | uint64(b[i])
+		}
+	}
+	return v
+}
+
+// putUint writes the low bytes of v to all of b.
+func putUint(b []byte, v uint64, little bool) {
+	for i := range b {
+		if little {
+			b[i] = byte(v)
+		} else {
+			b[len(b)-1-i] = byte(v)
+		}
+		v >>= 8
+	}
+}
+
+// signExtend interprets the low size bytes of v as a two's complement integer.
+func signExtend(v uint64, size int) int64 {
+	shift := 64 - 8*size
+	return int64(v<<shift) >> shift
+}
+
+// field returns the size bytes of data at the offset argument, which defaults to 0,
+// and the offset just past them.
+func (m bufferMethods) field(data []byte, v sobek.Value, size int) ([]byte, int) {
+	offset := 0.0
+	if !sobek.IsUndefined(v) {
+		offset = v.ToFloat()
+	}
+	if offset != math.Trunc(offset) || offset < 0 || offset+float64(size) > float64(len(data)) {
+		common.Throw(m.rt, fmt.Errorf("%s: %d bytes at offset %v exceed the buffer length %d", ErrBufferOutOfRange, size, offset, len(data)))
+	}
+	end := int(offset) + size
+	return data[int(offset):end], end
+}
+
+// integer checks that v is an integer in [lo, hi] and returns it.
+func (m bufferMethods) integer(v sobek.Value, lo, hi float64) float64 {
+	f := v.ToFloat()
+	if f != math.Trunc(f) || f < lo || f > hi {
+		common.Throw(m.rt, fmt.Errorf("%s: value must be an integer between %v and %v, got %s", ErrBufferOutOfRange, lo, hi, v.String()))
+	}
+	return f
+}
+
+// integerRange returns the range of a size-byte integer.
+func integerRange(size int, signed bool) (lo, hi float64) {
+	bits := float64(8 * size)
+	if signed {
+		return -math.Pow(2, bits-1), math.Pow(2, bits-1) - 1
+	}
+	return 0, math.Pow(2, bits) - 1
+}
+
+// reader returns the read method for an accessor, taking an optional offset.
+func (m bufferMethods) reader(a bufferAccessor) func(sobek.FunctionCall) sobek.Value {
+	return func(call sobek.FunctionCall) sobek.Value {
+		field, _ := m.field(m.this(call), call.Argument(0), a.size)
+		v := readUint(field, a.little)
+		switch {
+		case a.kind == 'f' && a.size == 4:
+			return m.rt.ToValue(float64(math.Float32frombits(uint32(v))))
+		case a.kind == 'f':
+			return m.rt.ToValue(math.Float64frombits(v))
+		case a.kind == 'b' && a.signed:
+			return m.rt.ToValue(big.NewInt(int64(v)))
+		case a.kind == 'b':
+			return m.rt.ToValue(new(big.Int).SetUint64(v))
+		case a.signed:
+			return m.rt.ToValue(signExtend(v, a.size))
+		}
+		return m.rt.ToValue(v)
+	}
+}
+
+// writer returns the write method for an accessor, taking a value and an optional
+// offset and returning the offset after the bytes written.
+func (m bufferMethods) writer(a bufferAccessor) func(sobek.FunctionCall) sobek.Value {
+	return func(call sobek.FunctionCall) sobek.Value {
+		field, end := m.field(m.this(call), call.Argument(1), a.size)
+		value := call.Argument(0)
+		var v uint64
+		switch a.kind {
+		case 'f':
+			if a.size == 4 {
+				v = uint64(math.Float32bits(float32(value.ToFloat())))
+			} else {
+				v = math.Float64bits(value.ToFloat())
+			}
+		case 'b':
+			n, ok := value.Export().(*big.Int)
+			lo, hi := new(big.Int), new(big.Int).SetUint64(math.MaxUint64)
+			if a.signed {
+				lo, hi = big.NewInt(math.MinInt64), big.NewInt(math.MaxInt64)
+			}
+			if !ok || n.Cmp(lo) < 0 || n.Cmp(hi) > 0 {
+				common.Throw(m.rt, fmt.Errorf("%s: value must be a BigInt between %s and %s, got %s", ErrBufferOutOfRange, lo, hi, value.String()))
+			}
+			if a.signed {
+				v = uint64(n.Int64())
+			} else {
+				v = n.Uint64()
+			}
+		default:
+			lo, hi := integerRange(a.size, a.signed)
+			v = uint64(int64(m.integer(value, lo, hi)))
+		}
+		putUint(field, v, a.little)
+		return m.rt.ToValue(end)
+	}
+}
+
+// variableField resolves the offset and byteLength arguments of readUIntBE and friends.
+func (m bufferMethods) variableField(data []byte, offset, byteLength sobek.Value) ([]byte, int) {
+	size := byteLength.ToFloat()
+	if size != math.Trunc(size) || size < 1 || size > 6 {
+		common.Throw(m.rt, fmt.Errorf("%s: byteLength must be an integer between 1 and 6, got %s", ErrBufferOutOfRange, byteLength.String()))
+	}
+	return m.field(data, offset, int(size))
+}
+
+// readVariable returns readUIntBE, readUIntLE, readIntBE or readIntLE, which read
+// integers of 1 to 6 bytes.
+func (m bufferMethods) readVariable(name string) func(sobek.FunctionCall) sobek.Value {
+	signed, little := !strings.HasPrefix(name, "U"), strings.HasSuffix(name, "LE")
+	return func(call sobek.FunctionCall) sobek.Value {
+		field, _ := m.variableField(m.this(call), call.Argument(0), call.Argument(1))
+		v := readUint(field, little)
+		if signed {
+			return m.rt.ToValue(signExtend(v, len(field)))
+		}
+		return m.rt.ToValue(v)
+	}
+}
+
+// writeVariable returns writeUIntBE, writeUIntLE, writeIntBE or writeIntLE, which
+// take a value, an offset and a byteLength of 1 to 6.
+func (m bufferMethods) writeVariable(name string) func(sobek.FunctionCall) sobek.Value {
+	signed, little := !strings.HasPrefix(name, "U"), strings.HasSuffix(name, "LE")
+	return func(call sobek.FunctionCall) sobek.Value {
+		field, end := m.variableField(m.this(call), call.Argument(1), call.Argument(2))
+		lo, hi := integerRange(len(field), signed)
+		putUint(field, uint64(int64(m.integer(call.Argument(0), lo, hi))), little)
+		return m.rt.ToValue(end)
+	}
+}
@@ -0,0 +1,188 @@
+package text_encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBufferEncodings(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	tests := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{name: "utf8", script: `Buffer.from('héllo').toString('hex')`, expected: "68c3a96c6c6f"},
+		{name: "default encoding", script: `Buffer.from('héllo').toString()`, expected: "héllo"},
+		{name: "hex", script: `Buffer.from('68C3A96C6C6F', 'hex').toString('utf-8')`, expected: "héllo"},
+		{name: "base64", script: `Buffer.from('aGVsbG8=', 'base64').toString()`, expected: "hello"},
+		{name: "base64 without padding", script: `Buffer.from('aGVsbG8', 'base64').toString()`, expected: "hello"},
+		{name: "base64url", script: `Buffer.from([0xFB, 0xFF]).toString('base64url')`, expected: "-_8"},
+		{name: "base64url decode", script: `Buffer.from('-_8', 'base64url').toString('hex')`, expected: "fbff"},
+		{name: "latin1", script: `Buffer.from('æø', 'latin1').toString('hex')`, expected: "e6f8"},
+		{name: "binary alias", script: `Buffer.from([0xE6, 0xF8]).toString('binary')`, expected: "æø"},
+		{name: "ascii strips high bit", script: `Buffer.from([0xE1, 0x62]).toString('ascii')`, expected: "ab"},
+		{name: "utf16le", script: `Buffer.from('hi€', 'utf16le').toString('hex')`, expected: "68006900ac20"},
+		{name: "ucs2 decode", script: `Buffer.from('68006900ac20', 'hex').toString('ucs2')`, expected: "hi€"},
+		{name: "lone surrogate utf16le", script: `Buffer.from('\uD800', 'utf16le').toString('utf16le') === '\uD800'`, expected: "true"},
+		{name: "invalid utf8 replaced", script: `Buffer.from([0x61, 0xFF, 0x62]).toString()`, expected: "a�b"},
+		{name: "toString range", script: `Buffer.from('hello world').toString('utf8', 6, 11)`, expected: "world"},
+		{name: "byteLength", script: `[Buffer.byteLength('héllo'), Buffer.byteLength('aGVsbG8=', 'base64'), Buffer.byteLength(new Uint8Array(3))].join()`, expected: "6,5,3"},
+		{name: "isEncoding", script: `[Buffer.isEncoding('UTF-8'), Buffer.isEncoding('ucs-2'), Buffer.isEncoding('utf32'), Buffer.isEncoding(1)].join()`, expected: "true,true,false,false"},
+	}
+
+	if _, err := rt.RunString(`var Buffer = encoding.Buffer`); err != nil {
+		t.Fatalf("failed to bind Buffer: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runScript(t, rt, tt.script).String()
+			if result != tt.expected {
+				t.Errorf("%s = %q, want %q", tt.script, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBufferMethods(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	tests := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{name: "is a Uint8Array", script: `const b = Buffer.from([1, 2, 3]); return [b instanceof Uint8Array, b.length, b[1], Buffer.isBuffer(b), Buffer.isBuffer(new Uint8Array(1))].join()`, expected: "true,3,2,true,false"},
+		{name: "instanceof", script: `const b = Buffer.from('a'); return [b instanceof Buffer, b.subarray(0) instanceof Buffer, new Uint8Array(1) instanceof Buffer, b.constructor === Buffer, Buffer.prototype === Object.getPrototypeOf(b), encoding.Buffer === Buffer].join()`, expected: "true,true,false,true,true,true"},
+		{name: "new", script: `const b = new Buffer(3); const s = new Buffer('hi'); return [b instanceof Buffer, b.join(), s.toString(), Buffer.isBuffer(s)].join('|')`, expected: "true|0,0,0|hi|true"},
+		{name: "species", script: `const b = Buffer.from([1, 2, 3]); const m = b.map((x) => x * 2); const f = b.filter((x) => x > 1); return [Buffer[Symbol.species] === Buffer, m instanceof Buffer, m.toString('hex'), Buffer.isBuffer(f), f.toString('hex'), Buffer.isBuffer(Buffer.of(1))].join()`, expected: "true,true,020406,true,0203,true"},
+		{name: "from array buffer shares memory", script: `const ab = new ArrayBuffer(4); const b = Buffer.from(ab, 1, 2); b[0] = 9; return [new Uint8Array(ab).join(), b.length].join('|')`, expected: "0,9,0,0|2"},
+		{name: "from array buffer at its end", script: `return [Buffer.from(new ArrayBuffer(4), 4).length, Buffer.from(new ArrayBuffer(4), 1).length].join()`, expected: "0,3"},
+		{name: "from typed array copies", script: `const u = new Uint8Array([1, 2]); const b = Buffer.from(u); b[0] = 9; return u.join()`, expected: "1,2"},
+		{name: "from json", script: `return Buffer.from(JSON.parse(JSON.stringify(Buffer.from('hi')))).toString()`, expected: "hi"},
+		{name: "toJSON", script: `return JSON.stringify(Buffer.from([1, 255]))`, expected: `{"type":"Buffer","data":[1,255]}`},
+		{name: "alloc", script: `return [Buffer.alloc(3).join(), Buffer.alloc(5, 'ab').toString(), Buffer.alloc(3, 0x41).toString(), Buffer.alloc(4, 'YQ==', 'base64').toString(), Buffer.allocUnsafe(2).length].join('|')`, expected: "0,0,0|ababa|AAA|aaaa|2"},
+		{name: "concat", script: `return [Buffer.concat([Buffer.from('ab'), new Uint8Array([0x63])]).toString(), Buffer.concat([Buffer.from('abc')], 2).toString(), Buffer.concat([Buffer.from('a')], 3).join(), Buffer.concat([]).length].join('|')`, expected: "abc|ab|97,0,0|0"},
+		{name: "compare", script: `return [Buffer.compare(Buffer.from('a'), Buffer.from('b')), Buffer.from('b').compare(Buffer.from('a')), Buffer.from('abc').compare(Buffer.from('xbc'), 1, 3, 1, 3), [Buffer.from('b'), Buffer.from('a')].sort(Buffer.compare).join('')].join()`, expected: "-1,1,0,ab"},
+		{name: "compare with start after end", script: `const b = Buffer.from([1, 2, 3]); return [b.compare(Buffer.from([1, 2, 3]), 2, 1), b.compare(Buffer.from([1, 2, 3]), 0, 3, 2, 1), Buffer.alloc(0).compare(b, 3, 0)].join()`, expected: "1,-1,0"},
+		{name: "equals", script: `return [Buffer.from('a').equals(Buffer.from('a')), Buffer.from('a').equals(new Uint8Array([98]))].join()`, expected: "true,false"},
+		{name: "slice shares memory", script: `const b = Buffer.from('hello'); const s = b.slice(1, -1); s[0] = 0x45; return [b.toString(), s.toString(), Buffer.isBuffer(s), Buffer.isBuffer(b.subarray(2))].join()`, expected: "hEllo,Ell,true,true"},
+		{name: "nested slices", script: `return Buffer.from('abcdef').subarray(1).subarray(2, 4).toString()`, expected: "de"},
+		{name: "indexOf", script: `const b = Buffer.from('abcabc'); return [b.indexOf('bc'), b.indexOf('bc', 2), b.indexOf(0x63), b.indexOf(Buffer.from('ca')), b.indexOf('zz'), b.indexOf('YmM=', 'base64'), b.indexOf('c', -2)].join()`, expected: "1,4,2,2,-1,1,5"},
+		{name: "lastIndexOf and includes", script: `const b = Buffer.from('abcabc'); return [b.lastIndexOf('bc'), b.lastIndexOf('bc', 3), b.lastIndexOf('a', -7), b.includes('cab'), b.includes('x')].join()`, expected: "4,1,-1,true,false"},
+		{name: "write", script: `const b = Buffer.alloc(6); const n = b.write('hi', 1); const m = b.write('ff00', 3, 'hex'); return [n, m, b.toString('hex')].join()`, expected: "2,2,006869ff0000"},
+		{name: "write does not split characters", script: `const b = Buffer.alloc(3); return [b.write('aé€'), b.toString('hex')].join()`, expected: "3,61c3a9"},
+		{name: "copy", script: `const b = Buffer.from('abcdef'); const t = Buffer.alloc(4); return [b.copy(t, 1, 2, 4), t.toString('hex'), b.copy(b, 0, 3), b.toString()].join()`, expected: "2,00636400,3,defdef"},
+		{name: "fill", script: `return [Buffer.alloc(5).fill('xy', 1).toString('latin1'), Buffer.alloc(3).fill(0x41, 1, 2).join(), Buffer.alloc(4).fill('6162', 'hex').toString()].join('|')`, expected: "\x00xyxy|0,65,0|abab"},
+	}
+
+	if _, err := rt.RunString(`var Buffer = encoding.Buffer`); err != nil {
+		t.Fatalf("failed to bind Buffer: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runScript(t, rt, "(() => { "+tt.script+" })()").String()
+			if result != tt.expected {
+				t.Errorf("%s = %q, want %q", tt.script, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBufferAccessors(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const Buffer = encoding.Buffer;
+		const b = Buffer.from('0102030405060708ff', 'hex');
+		return [
+			b.readUInt8(0), b.readUint8(8), b.readInt8(8),
+			b.readUInt16BE(0), b.readUInt16LE(0), b.readInt16BE(7),
+			b.readUInt32BE(0), b.readUint32LE(0), b.readInt32BE(5),
+			b.readBigUInt64BE(0), b.readBigInt64LE(1),
+			b.readUIntBE(0, 3), b.readIntLE(6, 3), b.readUintLE(0, 6),
+		].join();
+	})()`).String()
+	expected := "1,255,-1,258,513,2303,16909060,67305985,101124351,72623859790382856,-69798071788895486,66051,-63481,6618611909121"
+	if result != expected {
+		t.Errorf("reads = %q, want %q", result, expected)
+	}
+
+	result = runScript(t, rt, `(() => {
+		const b = encoding.Buffer.alloc(8);
+		const offsets = [
+			b.writeUInt32BE(0xDEADBEEF, 0), b.writeInt16LE(-2, 4), b.writeUInt8(0x7F, 6), b.writeInt8(-128, 7),
+		];
+		const floats = encoding.Buffer.alloc(12);
+		floats.writeFloatLE(1.5, 0);
+		floats.writeDoubleBE(-0.25, 4);
+		const big = encoding.Buffer.alloc(16);
+		big.writeBigUInt64LE(2n ** 64n - 1n, 0);
+		big.writeBigInt64BE(-2n, 8);
+		const vars = encoding.Buffer.alloc(6);
+		vars.writeUIntBE(0x123456, 0, 3);
+		vars.writeIntLE(-2, 3, 3);
+		return [
+			offsets.join(' '), b.toString('hex'),
+			floats.readFloatLE(0), floats.readDoubleBE(4), floats.toString('hex'),
+			big.readBigUInt64LE(0), big.readBigInt64BE(8),
+			vars.toString('hex'),
+		].join('|');
+	})()`).String()
+	expected = "4 6 7 8|deadbeeffeff7f80|1.5|-0.25|0000c03fbfd0000000000000|18446744073709551615|-2|123456feffff"
+	if result != expected {
+		t.Errorf("writes = %q, want %q", result, expected)
+	}
+
+	for _, script := range []string{
+		`encoding.Buffer.alloc(3).readUInt32BE(0)`,
+		`encoding.Buffer.alloc(4).readUInt16LE(3)`,
+		`encoding.Buffer.alloc(4).readUInt8(-1)`,
+		`encoding.Buffer.alloc(4).readUInt8(1.5)`,
+		`encoding.Buffer.alloc(4).writeUInt8(256)`,
+		`encoding.Buffer.alloc(4).writeInt8(-129)`,
+		`encoding.Buffer.alloc(4).writeUInt32BE(-1)`,
+		`encoding.Buffer.alloc(8).writeBigUInt64BE(-1n)`,
+		`encoding.Buffer.alloc(8).writeBigInt64BE(1)`,
+		`encoding.Buffer.alloc(8).readUIntBE(0, 7)`,
+	} {
+		if _, err := rt.RunString(script); err == nil || !strings.Contains(err.Error(), ErrBufferOutOfRange) {
+			t.Errorf("%s error = %v, want %q", script, err, ErrBufferOutOfRange)
+		}
+	}
+}
+
+func TestBufferErrors(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	tests := []struct {
+		script   string
+		expected string
+	}{
+		{script: `encoding.Buffer.from('abc', 'utf32')`, expected: ErrUnknownEncoding + `: "utf32"`},
+		{script: `encoding.Buffer.from('abz', 'hex')`, expected: ErrInvalidHex},
+		{script: `encoding.Buffer.from('a!b', 'base64')`, expected: ErrInvalidBase64},
+		{script: `encoding.Buffer.from('€', 'latin1')`, expected: ErrInvalidBinaryString},
+		{script: `encoding.Buffer.from(42)`, expected: ErrNotBufferInput},
+		{script: `encoding.Buffer.from(null)`, expected: ErrNotBufferInput},
+		{script: `encoding.Buffer.from(new ArrayBuffer(4), 2, 3)`, expected: ErrBufferOutOfRange},
+		{script: `encoding.Buffer.from(new ArrayBuffer(4), 5)`, expected: ErrBufferOutOfRange + ": offset 5 is outside the ArrayBuffer length 4"},
+		{script: `encoding.Buffer.from(new ArrayBuffer(4), -1)`, expected: ErrBufferOutOfRange + ": offset -1"},
+		{script: `encoding.Buffer.alloc(-1)`, expected: ErrInvalidOption},
+		{script: `encoding.Buffer.alloc(2, '')`, expected: "fill value is empty"},
+		{script: `encoding.Buffer.concat([Buffer.from('a'), 'b'])`, expected: ""},
+		{script: `encoding.Buffer.from('a').toString('utf32')`, expected: ErrUnknownEncoding},
+		{script: `encoding.Buffer.from('a').write('b', 2)`, expected: ErrBufferOutOfRange},
+	}
+
+	if _, err := rt.RunString(`var Buffer = encoding.Buffer`); err != nil {
+		t.Fatalf("failed to bind Buffer: %v", err)
+	}
+	for _, tt := range tests {
+		_, err := rt.RunString(tt.script)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("%s error = %v, want %q", tt.script, err, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package text_encoding
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// ErrNotBufferSource is returned when a value is not an ArrayBuffer or a view on one.
+const ErrNotBufferSource = "value is not an ArrayBuffer, TypedArray or DataView"
+
+// bytesFromValue returns the bytes viewed by an ArrayBuffer, TypedArray or DataView.
+// Only the region covered by a view is returned, so subarrays decode correctly.
+// Undefined and null yield an empty slice.
+func bytesFromValue(rt *sobek.Runtime, v sobek.Value) ([]byte, error) {
+	if common.IsNullish(v) {
+		return []byte{}, nil
+	}
+	switch data := v.Export().(type) {
+	case sobek.ArrayBuffer:
+		return data.Bytes(), nil
+	case []byte:
+		return data, nil
+	}
+
+	if !isView(rt, v) {
+		return nil, errors.New(ErrNotBufferSource)
+	}
+	obj := v.ToObject(rt)
+	bufValue, offsetValue, lengthValue := obj.Get("buffer"), obj.Get("byteOffset"), obj.Get("byteLength")
+	if bufValue == nil || offsetValue == nil || lengthValue == nil {
+		return nil, errors.New(ErrNotBufferSource)
+	}
+	buf, ok := bufValue.Export().(sobek.ArrayBuffer)
+	if !ok {
+		return nil, errors.New(ErrNotBufferSource)
+	}
+	offset := offsetValue.ToInteger()
+	length := lengthValue.ToInteger()
+	data := buf.Bytes()
+	if offset < 0 || length < 0 || offset+length > int64(len(data)) {
+		return nil, errors.New(ErrNotBufferSource)
+	}
+	return data[offset : offset+length], nil
+}
+
+// isView reports whether v is a TypedArray or a DataView. Like ArrayBuffer.isView, it
+// checks what the object is rather than which properties it has, so a plain object with
+// a buffer property is not taken for a view.
+func isView(rt *sobek.Runtime, v sobek.Value) bool {
+	arrayBuffer, ok := rt.Get("ArrayBuffer").(*sobek.Object)
+	if !ok {
+		return false
+	}
+	check, ok := sobek.AssertFunction(arrayBuffer.Get("isView"))
+	if !ok {
+		return false
+	}
+	result, err := check(arrayBuffer, v)
+	return err == nil && result.ToBoolean()
+}
+
+// newUint8Array wraps data in a new JavaScript Uint8Array without copying it.
+func newUint8Array(rt *sobek.Runtime, data []byte) (*sobek.Object, error) {
+	return rt.New(rt.Get("Uint8Array"), rt.ToValue(rt.NewArrayBuffer(data)))
+}
+
+// stringFromValue returns the string value of a required string argument.
+// Undefined and null are rejected rather than converted to "undefined" or "null".
+func stringFromValue(v sobek.Value) (string, error) {
+	if common.IsNullish(v) {
+		return "", errors.New(ErrNilInput)
+	}
+	return v.String(), nil
+}
+
+// exportOptions converts an optional JavaScript options object to the Go struct
+// pointed to by opts. Undefined and null leave opts unchanged.
+func exportOptions(rt *sobek.Runtime, v sobek.Value, opts any) error {
+	if common.IsNullish(v) {
+		return nil
+	}
+	if err := rt.ExportTo(v, opts); err != nil {
+		return fmt.Errorf("%s: %w", ErrInvalidOption, err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package text_encoding
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+)
+
+// Error messages for legacy character set conversion
+const (
+	ErrInvalidCharsetBytes = "invalid bytes for encoding"
+	ErrUnmappableCharacter = "character cannot be encoded"
+)
+
+// CharsetOptions configures DecodeCharset.
+// With Fatal set, bytes that do not map to a character are an error
+// instead of being replaced with U+FFFD.
+type CharsetOptions struct {
+	Fatal bool `js:"fatal"`
+}
+
+// multiByteCharsets are the canonical names of the supported WHATWG CJK and UTF-16
+// encodings. All single-byte encodings are supported as well.
+var multiByteCharsets = map[string]bool{
+	"shift_jis":   true,
+	"euc-jp":      true,
+	"iso-2022-jp": true,
+	"gbk":         true,
+	"gb18030":     true,
+	"big5":        true,
+	"euc-kr":      true,
+	"utf-16le":    true,
+	"utf-16be":    true,
+}
+
+// charset is an encoding resolved from a WHATWG label.
+// enc is nil for UTF-8, which is handled natively.
+type charset struct {
+	name string
+	enc  encoding.Encoding
+}
+
+// lookupCharset resolves a WHATWG encoding label such as "latin1" or "cp1251".
+// Matching is case-insensitive and ignores surrounding whitespace.
+func lookupCharset(label string) (*charset, error) {
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q", ErrUnsupportedEncoding, label)
+	}
+	name, err := htmlindex.Name(enc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q", ErrUnsupportedEncoding, label)
+	}
+	if name == "utf-8" {
+		return &charset{name: name}, nil
+	}
+	if _, ok := enc.(*charmap.Charmap); !ok && !multiByteCharsets[name] {
+		return nil, fmt.Errorf("%s: %q", ErrUnsupportedEncoding, label)
+	}
+	return &charset{name: name, enc: enc}, nil
+}
+
+// utf16Order returns the byte order of a UTF-16 character set, or nil for any other.
+// UTF-16 is decoded by decodeUTF16Chunk rather than by x/text, whose decoder cannot
+// tell an encoded U+FFFD from a malformed sequence.
+func (cs *charset) utf16Order() byteOrder {
+	switch cs.name {
+	case "utf-16le":
+		return binary.LittleEndian
+	case "utf-16be":
+		return binary.BigEndian
+	}
+	return nil
+}
+
+// encode converts UTF-8 text to the character set.
+// Characters the character set cannot represent are reported with their offset.
+func (cs *charset) encode(text string) ([]byte, error) {
+	out, _, err := cs.encodeWithFallback(text, FallbackError)
+	return out, err
+}
+
+// decode converts bytes in the character set to UTF-8 text.
+// Malformed sequences are replaced with U+FFFD, or reported as an error when fatal is set.
+func (cs *charset) decode(data []byte, fatal bool) (string, error) {
+	if cs.enc == nil {
+		text, _, _, err := decodeUTF8Chunk(data, fatal, true)
+		return text, err
+	}
+	if order := cs.utf16Order(); order != nil {
+		text, _, err := decodeUTF16Chunk(data, order, !fatal, true, 0)
+		return text, err
+	}
+	out, _, err := transform.Bytes(cs.enc.NewDecoder(), data)
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %w", ErrInvalidCharsetBytes, cs.name, err)
+	}
+	text := string(out)
+	if fatal && cs.malformed(data, text) {
+		return "", fmt.Errorf("%s %s", ErrInvalidCharsetBytes, cs.name)
+	}
+	return text, nil
+}
+
+// malformed reports whether data, which the character set decoded to text, holds a
+// malformed sequence. The decoders substitute U+FFFD for those, so a U+FFFD in text is
+// one unless the character set can encode U+FFFD itself, as GB18030 can, and data holds
+// that encoding where it was decoded from.
+func (cs *charset) malformed(data []byte, text string) bool {
+	if !strings.ContainsRune(text, utf8.RuneError) {
+		return false
+	}
+	replacement, err := cs.enc.NewEncoder().Bytes([]byte(string(utf8.RuneError)))
+	if err != nil {
+		return true
+	}
+
+	dec := cs.enc.NewDecoder()
+	buf := make([]byte, utf8.UTFMax)
+	for len(data) > 0 {
+		// Three bytes hold U+FFFD but no other character with it, so a U+FFFD comes out
+		// on its own together with exactly the bytes it was decoded from.
+		nDst, nSrc, err := dec.Transform(buf[:3], data, true)
+		if nDst == 0 && errors.Is(err, transform.ErrShortDst) {
+			nDst, nSrc, err = dec.Transform(buf, data, true)
+		}
+		if nSrc == 0 || (err != nil && !errors.Is(err, transform.ErrShortDst)) {
+			return true
+		}
+		if string(buf[:nDst]) == string(utf8.RuneError) && !bytes.Equal(data[:nSrc], replacement) {
+			return true
+		}
+		data = data[nSrc:]
+	}
+	return false
+}
+
+// EncodeCharset converts a string to bytes in the encoding named by a WHATWG label,
+// such as "windows-1252", "koi8-r", "shift_jis", "gb18030" or "euc-kr", and returns them
+// as an ArrayBuffer. It throws if the text contains a character the encoding cannot represent.
+func (TextEncoding) EncodeCharset(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	text, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := encodeCharset(text, call.Argument(1).String())
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(encoded))
+}
+
+// encodeCharset converts text to bytes in the encoding named by label.
+func encodeCharset(text string, label string) ([]byte, error) {
+	if err := validateInputSize(len(text)); err != nil {
+		return nil, err
+	}
+	if err := validateUTF8String(text); err != nil {
+		return nil, err
+	}
+	cs, err := lookupCharset(label)
+	if err != nil {
+		return nil, err
+	}
+	return cs.encode(text)
+}
+
+// DecodeCharset converts bytes in the encoding named by a WHATWG label to a string.
+// Bytes that do not map to a character are replaced with U+FFFD unless the fatal option is set.
+func (TextEncoding) DecodeCharset(data []byte, label string, opts ...CharsetOptions) (string, error) {
+	if err := validateInputSize(len(data)); err != nil {
+		return "", err
+	}
+	cs, err := lookupCharset(label)
+	if err != nil {
+		return "", err
+	}
+	var fatal bool
+	if len(opts) > 0 {
+		fatal = opts[0].Fatal
+	}
+	return cs.decode(data, fatal)
+}
@@ -0,0 +1,274 @@
+package text_encoding
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLookupCharset(t *testing.T) {
+	tests := []struct {
+		label       string
+		expected    string
+		expectError bool
+	}{
+		{label: "utf-8", expected: "utf-8"},
+		{label: "windows-1252", expected: "windows-1252"},
+		{label: "latin1", expected: "windows-1252"},
+		{label: "ISO-8859-1", expected: "windows-1252"},
+		{label: "cp1251", expected: "windows-1251"},
+		{label: " ISO-8859-2 ", expected: "iso-8859-2"},
+		{label: "iso_8859-5", expected: "iso-8859-5"},
+		{label: "l9", expected: "iso-8859-15"},
+		{label: "koi8-r", expected: "koi8-r"},
+		{label: "koi8-u", expected: "koi8-u"},
+		{label: "mac", expected: "macintosh"},
+		{label: "x-mac-cyrillic", expected: "x-mac-cyrillic"},
+		{label: "dos-874", expected: "windows-874"},
+		{label: "ibm866", expected: "ibm866"},
+		{label: "klingon", expectError: true},
+		{label: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			cs, err := lookupCharset(tt.label)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("lookupCharset(%q) expected error but got none", tt.label)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("lookupCharset(%q) unexpected error: %v", tt.label, err)
+			}
+			if cs.name != tt.expected {
+				t.Errorf("lookupCharset(%q) = %q, want %q", tt.label, cs.name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSingleByteCharsets(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		label string
+		text  string
+		bytes []byte
+	}{
+		{label: "windows-1252", text: "café €5", bytes: []byte{'c', 'a', 'f', 0xE9, ' ', 0x80, '5'}},
+		{label: "iso-8859-2", text: "Łódź", bytes: []byte{0xA3, 0xF3, 'd', 0xBC}},
+		{label: "iso-8859-5", text: "Мир", bytes: []byte{0xBC, 0xD8, 0xE0}},
+		{label: "iso-8859-15", text: "€œ", bytes: []byte{0xA4, 0xBD}},
+		{label: "koi8-r", text: "Привет", bytes: []byte{0xF0, 0xD2, 0xC9, 0xD7, 0xC5, 0xD4}},
+		{label: "windows-1251", text: "Привет", bytes: []byte{0xCF, 0xF0, 0xE8, 0xE2, 0xE5, 0xF2}},
+		{label: "macintosh", text: "café", bytes: []byte{'c', 'a', 'f', 0x8E}},
+		{label: "utf-8", text: "café", bytes: []byte("café")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			encoded, err := encodeCharset(tt.text, tt.label)
+			if err != nil {
+				t.Fatalf("encodeCharset() unexpected error: %v", err)
+			}
+			if !bytes.Equal(encoded, tt.bytes) {
+				t.Errorf("encodeCharset(%q, %q) = % X, want % X", tt.text, tt.label, encoded, tt.bytes)
+			}
+
+			decoded, err := te.DecodeCharset(tt.bytes, tt.label)
+			if err != nil {
+				t.Fatalf("DecodeCharset() unexpected error: %v", err)
+			}
+			if decoded != tt.text {
+				t.Errorf("DecodeCharset(% X, %q) = %q, want %q", tt.bytes, tt.label, decoded, tt.text)
+			}
+		})
+	}
+}
+
+func TestCharsetErrors(t *testing.T) {
+	te := &TextEncoding{}
+
+	// Characters outside the code page cannot be encoded.
+	_, err := encodeCharset("abc 你", "windows-1252")
+	if err == nil {
+		t.Fatal("encodeCharset() expected error for unmappable character")
+	}
+	if !strings.Contains(err.Error(), ErrUnmappableCharacter) || !strings.Contains(err.Error(), "offset 4") {
+		t.Errorf("encodeCharset() error = %v, want unmappable character at offset 4", err)
+	}
+
+	// 0xAA is undefined in windows-1253.
+	decoded, err := te.DecodeCharset([]byte{'a', 0xAA}, "windows-1253")
+	if err != nil {
+		t.Fatalf("DecodeCharset() unexpected error: %v", err)
+	}
+	if decoded != "a\uFFFD" {
+		t.Errorf("DecodeCharset() = %q, want %q", decoded, "a\uFFFD")
+	}
+	_, err = te.DecodeCharset([]byte{'a', 0xAA}, "windows-1253", CharsetOptions{Fatal: true})
+	if err == nil {
+		t.Error("DecodeCharset() expected error in fatal mode")
+	}
+
+	if _, err := encodeCharset("abc", "klingon"); err == nil {
+		t.Error("encodeCharset() expected error for unknown label")
+	}
+	if _, err := te.DecodeCharset([]byte("abc"), "klingon"); err == nil {
+		t.Error("DecodeCharset() expected error for unknown label")
+	}
+	if _, err := encodeCharset(string([]byte{0xFF}), "windows-1252"); err == nil {
+		t.Error("encodeCharset() expected error for invalid UTF-8 input")
+	}
+	if _, err := te.DecodeCharset(make([]byte, MaxInputSize+1), "windows-1252"); err == nil {
+		t.Error("DecodeCharset() expected error for oversized input")
+	}
+}
+
+func TestMultiByteCharsets(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		label string
+		text  string
+		bytes []byte
+	}{
+		{label: "shift_jis", text: "日本語", bytes: []byte{0x93, 0xFA, 0x96, 0x7B, 0x8C, 0xEA}},
+		{label: "euc-jp", text: "日本語", bytes: []byte{0xC6, 0xFC, 0xCB, 0xDC, 0xB8, 0xEC}},
+		{
+			label: "iso-2022-jp",
+			text:  "a日本語b",
+			bytes: []byte{'a', 0x1B, 0x24, 0x42, 0x46, 0x7C, 0x4B, 0x5C, 0x38, 0x6C, 0x1B, 0x28, 0x42, 'b'},
+		},
+		{label: "gbk", text: "中文", bytes: []byte{0xD6, 0xD0, 0xCE, 0xC4}},
+		{
+			label: "gb18030",
+			text:  "中文\u0080😀\uFFFD",
+			bytes: []byte{0xD6, 0xD0, 0xCE, 0xC4, 0x81, 0x30, 0x81, 0x30, 0x94, 0x39, 0xFC, 0x36, 0x84, 0x31, 0xA4, 0x37},
+		},
+		{label: "big5", text: "中文", bytes: []byte{0xA4, 0xA4, 0xA4, 0xE5}},
+		{label: "euc-kr", text: "한국어", bytes: []byte{0xC7, 0xD1, 0xB1, 0xB9, 0xBE, 0xEE}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			encoded, err := encodeCharset(tt.text, tt.label)
+			if err != nil {
+				t.Fatalf("encodeCharset() unexpected error: %v", err)
+			}
+			if !bytes.Equal(encoded, tt.bytes) {
+				t.Errorf("encodeCharset(%q, %q) = % X, want % X", tt.text, tt.label, encoded, tt.bytes)
+			}
+
+			decoded, err := te.DecodeCharset(tt.bytes, tt.label, CharsetOptions{Fatal: true})
+			if err != nil {
+				t.Fatalf("DecodeCharset() unexpected error: %v", err)
+			}
+			if decoded != tt.text {
+				t.Errorf("DecodeCharset(% X, %q) = %q, want %q", tt.bytes, tt.label, decoded, tt.text)
+			}
+		})
+	}
+}
+
+func TestMultiByteCharsetLabels(t *testing.T) {
+	labels := map[string]string{
+		"sjis":           "shift_jis",
+		"windows-31j":    "shift_jis",
+		"x-euc-jp":       "euc-jp",
+		"csiso2022jp":    "iso-2022-jp",
+		"gb2312":         "gbk",
+		"x-gbk":          "gbk",
+		"GB18030":        "gb18030",
+		"big5-hkscs":     "big5",
+		"ks_c_5601-1987": "euc-kr",
+	}
+	for label, expected := range labels {
+		cs, err := lookupCharset(label)
+		if err != nil {
+			t.Errorf("lookupCharset(%q) unexpected error: %v", label, err)
+			continue
+		}
+		if cs.name != expected {
+			t.Errorf("lookupCharset(%q) = %q, want %q", label, cs.name, expected)
+		}
+	}
+
+	// The replacement encoding only exists to block decoding and is not supported.
+	if _, err := lookupCharset("replacement"); err == nil {
+		t.Error("lookupCharset(\"replacement\") expected error")
+	}
+}
+
+func TestMultiByteCharsetErrors(t *testing.T) {
+	te := &TextEncoding{}
+
+	// A Shift_JIS lead byte followed by an invalid trail byte.
+	invalid := []byte{'a', 0x81, 0x20, 'b'}
+	decoded, err := te.DecodeCharset(invalid, "shift_jis")
+	if err != nil {
+		t.Fatalf("DecodeCharset() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(decoded, "a\uFFFD") || !strings.HasSuffix(decoded, "b") {
+		t.Errorf("DecodeCharset() = %q, want replacement between a and b", decoded)
+	}
+	if _, err := te.DecodeCharset(invalid, "shift_jis", CharsetOptions{Fatal: true}); err == nil {
+		t.Error("DecodeCharset() expected error in fatal mode")
+	}
+
+	// A truncated EUC-KR sequence at the end of input.
+	if _, err := te.DecodeCharset([]byte{0xC7}, "euc-kr", CharsetOptions{Fatal: true}); err == nil {
+		t.Error("DecodeCharset() expected error for truncated sequence in fatal mode")
+	}
+
+	// GB18030 encodes U+FFFD, which must not hide a malformed sequence next to it.
+	if _, err := te.DecodeCharset([]byte{0x84, 0x31, 0xA4, 0x37, 0xFF}, "gb18030", CharsetOptions{Fatal: true}); err == nil {
+		t.Error("DecodeCharset() expected error for malformed GB18030 in fatal mode")
+	}
+
+	// UTF-16 only fails on unpaired surrogates and odd lengths.
+	if text, err := te.DecodeCharset([]byte{0xFD, 0xFF, 0x41, 0x00}, "utf-16le", CharsetOptions{Fatal: true}); err != nil || text != "\uFFFDA" {
+		t.Errorf("DecodeCharset() = %q, %v, want %q", text, err, "\uFFFDA")
+	}
+	if _, err := te.DecodeCharset([]byte{0x00, 0x41, 0xDC, 0x00}, "utf-16be", CharsetOptions{Fatal: true}); err == nil {
+		t.Error("DecodeCharset() expected error for unpaired surrogate in fatal mode")
+	}
+
+	// Hangul is not representable in Shift_JIS.
+	_, err = encodeCharset("日本 한", "shift_jis")
+	if err == nil || !strings.Contains(err.Error(), "offset 7") {
+		t.Errorf("encodeCharset() error = %v, want unmappable character at offset 7", err)
+	}
+}
+
+func TestCharsetJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const bytes = encoding.encodeCharset('café €5', 'windows-1252');
+		const jis = encoding.encodeCharset('a日本b', 'iso-2022-jp');
+		return [
+			bytes instanceof ArrayBuffer,
+			new Uint8Array(bytes).join(','),
+			encoding.decodeCharset(bytes, 'cp1252'),
+			encoding.decodeCharset(jis, 'csiso2022jp'),
+		].join('|');
+	})()`).String()
+	expected := "true|99,97,102,233,32,128,53|café €5|a日本b"
+	if result != expected {
+		t.Errorf("charset = %q, want %q", result, expected)
+	}
+
+	throwing := []string{
+		`encoding.encodeCharset('你好', 'iso-8859-2')`,
+		`encoding.encodeCharset('abc', 'klingon')`,
+		`encoding.encodeCharset(null, 'windows-1252')`,
+	}
+	for _, script := range throwing {
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected to throw", script)
+		}
+	}
+}
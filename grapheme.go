@@ -0,0 +1,95 @@
+package text_encoding
+
+import (
+	"strings"
+
+	"github.com/grafana/sobek"
+	"github.com/rivo/uniseg"
+)
+
+// UnicodeSegmentationVersion is the Unicode version of the segmentation and width tables
+// of github.com/rivo/uniseg v0.4.7, which the grapheme functions use. Results for text
+// that uses characters added in later versions may change when the tables are updated.
+const UnicodeSegmentationVersion = "15.0.0"
+
+// validateText checks the size and UTF-8 validity of text passed to the segmentation functions.
+func validateText(text string) error {
+	if err := validateInputSize(len(text)); err != nil {
+		return err
+	}
+	return validateUTF8String(text)
+}
+
+// graphemes splits text into its UAX #29 extended grapheme clusters.
+func graphemes(text string) []string {
+	var clusters []string
+	state := -1
+	for len(text) > 0 {
+		var cluster string
+		cluster, text, _, state = uniseg.FirstGraphemeClusterInString(text, state)
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// UnicodeVersion returns the Unicode version of the grapheme, word, sentence and line
+// segmentation tables, so that results can be tied to the rules that produced them.
+func (TextEncoding) UnicodeVersion() string {
+	return UnicodeSegmentationVersion
+}
+
+// CountGraphemes returns the number of user-perceived characters (extended grapheme
+// clusters) in text. The family emoji "👨‍👩‍👧‍👦" is 7 runes but one grapheme.
+func (TextEncoding) CountGraphemes(text string) (int, error) {
+	if err := validateText(text); err != nil {
+		return 0, err
+	}
+	return uniseg.GraphemeClusterCount(text), nil
+}
+
+// SplitGraphemes splits text into its extended grapheme clusters.
+func (TextEncoding) SplitGraphemes(text string) ([]string, error) {
+	if err := validateText(text); err != nil {
+		return nil, err
+	}
+	clusters := graphemes(text)
+	if clusters == nil {
+		return []string{}, nil
+	}
+	return clusters, nil
+}
+
+// SubstringGraphemes returns the graphemes of text from index start up to, but not
+// including, index end, counting in graphemes. Like String.prototype.substring, negative
+// indexes count as 0, indexes past the end count as the end, the indexes are swapped if
+// start is greater than end, and an undefined or nil end means the end of text.
+func (TextEncoding) SubstringGraphemes(text string, start int, end sobek.Value) (string, error) {
+	if err := validateText(text); err != nil {
+		return "", err
+	}
+	clusters := graphemes(text)
+	to := len(clusters)
+	if end != nil && !sobek.IsUndefined(end) {
+		to = int(min(max(end.ToInteger(), 0), int64(len(clusters))))
+	}
+	from := min(max(start, 0), len(clusters))
+	if from > to {
+		from, to = to, from
+	}
+	return strings.Join(clusters[from:to], ""), nil
+}
+
+// ReverseGraphemes reverses text one grapheme at a time, so that combining marks,
+// emoji sequences and flags stay intact.
+func (TextEncoding) ReverseGraphemes(text string) (string, error) {
+	if err := validateText(text); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	clusters := graphemes(text)
+	for i := len(clusters) - 1; i >= 0; i-- {
+		b.WriteString(clusters[i])
+	}
+	return b.String(), nil
+}
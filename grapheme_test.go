@@ -0,0 +1,157 @@
+package text_encoding
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grafana/sobek"
+)
+
+func TestCountGraphemes(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{name: "empty", input: "", expected: 0},
+		{name: "ascii", input: "Hello", expected: 5},
+		{name: "family emoji", input: "👨‍👩‍👧‍👦", expected: 1},
+		{name: "rainbow flag", input: "🏳️‍🌈", expected: 1},
+		{name: "regional indicators", input: "🇩🇪🇫🇷", expected: 2},
+		{name: "skin tone", input: "👍🏽", expected: 1},
+		{name: "combining mark", input: "cafe\u0301", expected: 4},
+		{name: "hangul jamo", input: "\u1100\u1161\u11A8", expected: 1},
+		{name: "crlf", input: "a\r\nb", expected: 3},
+		// Conjuncts across a virama join only from Unicode 15.1.
+		{name: "devanagari", input: "नमस्ते", expected: 4},
+		{name: "zalgo", input: "Z͑ͫ̓ͪ̂ͫ̽͏̴̙̤̞͉͚̯̞̠͍A", expected: 2},
+		{name: "mixed", input: "Hello 🌍 你好 café résumé 안녕하세요 مرحبا 𝄞 𒀀 👨‍👩‍👧‍👦 🏳️‍🌈", expected: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := te.CountGraphemes(tt.input)
+			if err != nil {
+				t.Fatalf("CountGraphemes() unexpected error: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("CountGraphemes(%q) = %d, want %d", tt.input, count, tt.expected)
+			}
+			clusters, err := te.SplitGraphemes(tt.input)
+			if err != nil {
+				t.Fatalf("SplitGraphemes() unexpected error: %v", err)
+			}
+			if len(clusters) != tt.expected || strings.Join(clusters, "") != tt.input {
+				t.Errorf("SplitGraphemes(%q) = %q, want %d clusters", tt.input, clusters, tt.expected)
+			}
+		})
+	}
+
+	if _, err := te.CountGraphemes("\xff"); err == nil || !strings.Contains(err.Error(), ErrInvalidUTF8) {
+		t.Errorf("CountGraphemes() error = %v, want %q", err, ErrInvalidUTF8)
+	}
+}
+
+func TestSplitGraphemes(t *testing.T) {
+	te := &TextEncoding{}
+
+	clusters, err := te.SplitGraphemes("e\u0301👨‍👩‍👧‍👦🇩🇪\r\n")
+	if err != nil {
+		t.Fatalf("SplitGraphemes() unexpected error: %v", err)
+	}
+	expected := []string{"e\u0301", "👨‍👩‍👧‍👦", "🇩🇪", "\r\n"}
+	if !reflect.DeepEqual(clusters, expected) {
+		t.Errorf("SplitGraphemes() = %q, want %q", clusters, expected)
+	}
+
+	clusters, err = te.SplitGraphemes("")
+	if err != nil || clusters == nil || len(clusters) != 0 {
+		t.Errorf("SplitGraphemes(\"\") = %q, %v, want an empty slice", clusters, err)
+	}
+}
+
+func TestSubstringGraphemes(t *testing.T) {
+	te := &TextEncoding{}
+	rt := sobek.New()
+	text := "a👨‍👩‍👧‍👦e\u0301🇩🇪z"
+
+	tests := []struct {
+		name     string
+		start    int
+		end      sobek.Value
+		expected string
+	}{
+		{name: "middle", start: 1, end: rt.ToValue(3), expected: "👨‍👩‍👧‍👦e\u0301"},
+		{name: "no end", start: 3, expected: "🇩🇪z"},
+		{name: "undefined end", start: 3, end: sobek.Undefined(), expected: "🇩🇪z"},
+		{name: "null end", start: 1, end: sobek.Null(), expected: "a"},
+		{name: "swapped", start: 3, end: rt.ToValue(1), expected: "👨‍👩‍👧‍👦e\u0301"},
+		{name: "negative start", start: -2, end: rt.ToValue(1), expected: "a"},
+		{name: "past the end", start: 4, end: rt.ToValue(99), expected: "z"},
+		{name: "empty range", start: 2, end: rt.ToValue(2), expected: ""},
+		{name: "whole", start: 0, end: rt.ToValue(5), expected: text},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.SubstringGraphemes(text, tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("SubstringGraphemes() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("SubstringGraphemes(%d, %v) = %q, want %q", tt.start, tt.end, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReverseGraphemes(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: ""},
+		{input: "abc", expected: "cba"},
+		{input: "cafe\u0301!", expected: "!e\u0301fac"},
+		{input: "👍🏽👨‍👩‍👧‍👦🇩🇪", expected: "🇩🇪👨‍👩‍👧‍👦👍🏽"},
+		{input: "a\r\nb", expected: "b\r\na"},
+	}
+
+	for _, tt := range tests {
+		result, err := te.ReverseGraphemes(tt.input)
+		if err != nil {
+			t.Fatalf("ReverseGraphemes() unexpected error: %v", err)
+		}
+		if result != tt.expected {
+			t.Errorf("ReverseGraphemes(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestGraphemesJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const family = '👨‍👩‍👧‍👦';
+		return [
+			encoding.countUTF8Runes(family),
+			encoding.countGraphemes(family),
+			encoding.countGraphemes('cafe\u0301 ' + family),
+			encoding.splitGraphemes('e\u0301🇩🇪').length,
+			encoding.substringGraphemes('ab' + family + 'cd', 2, 3) === family,
+			encoding.substringGraphemes('ab' + family + 'cd', 3),
+			encoding.substringGraphemes('abcdef', 2, undefined),
+			encoding.reverseGraphemes('ab🇩🇪') === '🇩🇪ba',
+			encoding.unicodeVersion(),
+		].join('|');
+	})()`).String()
+	expected := "7|1|6|2|true|cd|cdef|true|" + UnicodeSegmentationVersion
+	if result != expected {
+		t.Errorf("graphemes = %q, want %q", result, expected)
+	}
+}
@@ -0,0 +1,237 @@
+package text_encoding
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// ErrInvalidHex is returned when hex input cannot be decoded.
+const ErrInvalidHex = "failed to decode hex"
+
+// Letter cases for HexOptions.Case.
+const (
+	// CaseLower writes the hex digits a-f in lower case. It is the default.
+	CaseLower = "lower"
+	// CaseUpper writes the hex digits A-F in upper case.
+	CaseUpper = "upper"
+)
+
+// Separators between bytes for HexOptions.Separator.
+const (
+	// SeparatorNone writes the bytes back to back. It is the default.
+	SeparatorNone = "none"
+	// SeparatorSpace separates bytes with a space.
+	SeparatorSpace = "space"
+	// SeparatorColon separates bytes with a colon.
+	SeparatorColon = "colon"
+)
+
+// HexOptions configures the hex encoders and decoders.
+// With Prefix set, "0x" is written once before the digits, or before every byte when a
+// separator is used. Decoding accepts either letter case. Lenient decoding ignores
+// whitespace and makes separators and prefixes optional.
+type HexOptions struct {
+	Case      string `js:"case"`
+	Separator string `js:"separator"`
+	Prefix    bool   `js:"prefix"`
+	Lenient   bool   `js:"lenient"`
+}
+
+// hexOptions returns the first of opts, or the zero options.
+func hexOptions(opts []HexOptions) HexOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return HexOptions{}
+}
+
+// separator resolves the Separator option to the string written between bytes.
+func (o HexOptions) separator() (string, error) {
+	switch strings.ToLower(o.Separator) {
+	case "", SeparatorNone:
+		return "", nil
+	case SeparatorSpace:
+		return " ", nil
+	case SeparatorColon:
+		return ":", nil
+	default:
+		return "", fmt.Errorf("%s: separator must be one of %q, %q or %q, got %q",
+			ErrInvalidOption, SeparatorNone, SeparatorSpace, SeparatorColon, o.Separator)
+	}
+}
+
+// encode converts data to hex as configured.
+func (o HexOptions) encode(data []byte) (string, error) {
+	var digits string
+	switch strings.ToLower(o.Case) {
+	case "", CaseLower:
+		digits = "0123456789abcdef"
+	case CaseUpper:
+		digits = "0123456789ABCDEF"
+	default:
+		return "", fmt.Errorf("%s: case must be %q or %q, got %q", ErrInvalidOption, CaseLower, CaseUpper, o.Case)
+	}
+	sep, err := o.separator()
+	if err != nil {
+		return "", err
+	}
+	if len(data) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(data) * (2 + len(sep) + 2))
+	if o.Prefix && sep == "" {
+		sb.WriteString("0x")
+	}
+	for i, b := range data {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		if o.Prefix && sep != "" {
+			sb.WriteString("0x")
+		}
+		sb.WriteByte(digits[b>>4])
+		sb.WriteByte(digits[b&0x0F])
+	}
+	return sb.String(), nil
+}
+
+// decode converts hex to bytes as configured. Errors report the offset of the
+// offending character in encoded.
+func (o HexOptions) decode(encoded string) ([]byte, error) {
+	sep, err := o.separator()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(encoded)/2)
+	for i := 0; i < len(encoded); {
+		c := encoded[i]
+		if o.Lenient && (isASCIISpace(c) || sep != "" && c == sep[0]) {
+			i++
+			continue
+		}
+		if !o.Lenient && len(out) > 0 && sep != "" {
+			if c != sep[0] {
+				return nil, fmt.Errorf("%s: expected %q at offset %d, got %q", ErrInvalidHex, sep, i, c)
+			}
+			i++
+		}
+
+		hasPrefix := i+1 < len(encoded) && encoded[i] == '0' && (encoded[i+1] == 'x' || encoded[i+1] == 'X')
+		switch {
+		case hasPrefix && (o.Lenient || o.Prefix && (len(out) == 0 || sep != "")):
+			i += 2
+		case !o.Lenient && o.Prefix && (len(out) == 0 || sep != ""):
+			return nil, fmt.Errorf("%s: expected \"0x\" at offset %d", ErrInvalidHex, i)
+		}
+
+		if i+1 >= len(encoded) {
+			return nil, fmt.Errorf("%s: incomplete byte at offset %d", ErrInvalidHex, i)
+		}
+		hi, ok := hexDigit(encoded[i])
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid character %q at offset %d", ErrInvalidHex, encoded[i], i)
+		}
+		lo, ok := hexDigit(encoded[i+1])
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid character %q at offset %d", ErrInvalidHex, encoded[i+1], i+1)
+		}
+		out = append(out, hi<<4|lo)
+		i += 2
+	}
+	return out, nil
+}
+
+// hexDigit returns the value of the hex digit c.
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// isASCIISpace reports whether c is an ASCII whitespace character.
+func isASCIISpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// EncodeHex converts the bytes of an ArrayBuffer, TypedArray or DataView to hex.
+// The options select the letter case, a separator between bytes and a "0x" prefix.
+func (TextEncoding) EncodeHex(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o HexOptions
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encode(data)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encoded)
+}
+
+// DecodeHex decodes hex to an ArrayBuffer.
+// It takes the same options as EncodeHex, plus lenient decoding.
+func (TextEncoding) DecodeHex(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o HexOptions
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	decoded, err := o.decode(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(decoded))
+}
+
+// EncodeUTF8ToHex converts a string to UTF-8 bytes and then to hex.
+// It takes the same options as EncodeHex.
+func (TextEncoding) EncodeUTF8ToHex(text string, opts ...HexOptions) (string, error) {
+	if err := validateInputSize(len(text)); err != nil {
+		return "", err
+	}
+	if err := validateUTF8String(text); err != nil {
+		return "", err
+	}
+	return hexOptions(opts).encode([]byte(text))
+}
+
+// DecodeUTF8FromHex decodes hex to UTF-8 text.
+// It validates both the hex encoding and the resulting UTF-8.
+func (TextEncoding) DecodeUTF8FromHex(encoded string, opts ...HexOptions) (string, error) {
+	if err := validateInputSize(len(encoded)); err != nil {
+		return "", err
+	}
+	decoded, err := hexOptions(opts).decode(encoded)
+	if err != nil {
+		return "", err
+	}
+	if err := validateUTF8Bytes(decoded); err != nil {
+		return "", errors.New(ErrInvalidDecodedUTF8)
+	}
+	return string(decoded), nil
+}
@@ -0,0 +1,148 @@
+package text_encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeUTF8ToHex(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     HexOptions
+		expected string
+	}{
+		{name: "empty", input: "", expected: ""},
+		{name: "default", input: "Hé", expected: "48c3a9"},
+		{name: "upper", input: "Hé", opts: HexOptions{Case: CaseUpper}, expected: "48C3A9"},
+		{name: "space", input: "Hé", opts: HexOptions{Separator: SeparatorSpace}, expected: "48 c3 a9"},
+		{name: "colon upper", input: "Hé", opts: HexOptions{Separator: "Colon", Case: "UPPER"}, expected: "48:C3:A9"},
+		{name: "prefix", input: "Hé", opts: HexOptions{Prefix: true}, expected: "0x48c3a9"},
+		{name: "prefix per byte", input: "Hé", opts: HexOptions{Prefix: true, Separator: SeparatorSpace}, expected: "0x48 0xc3 0xa9"},
+		{name: "empty with prefix", input: "", opts: HexOptions{Prefix: true}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.EncodeUTF8ToHex(tt.input, tt.opts)
+			if err != nil {
+				t.Fatalf("EncodeUTF8ToHex() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("EncodeUTF8ToHex(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeUTF8FromHex(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name        string
+		input       string
+		opts        HexOptions
+		expected    string
+		expectError string
+	}{
+		{name: "empty", input: "", expected: ""},
+		{name: "lower", input: "48c3a9", expected: "Hé"},
+		{name: "mixed case", input: "48C3a9", expected: "Hé"},
+		{name: "space", input: "48 c3 a9", opts: HexOptions{Separator: SeparatorSpace}, expected: "Hé"},
+		{name: "colon", input: "48:c3:a9", opts: HexOptions{Separator: SeparatorColon}, expected: "Hé"},
+		{name: "prefix", input: "0x48c3a9", opts: HexOptions{Prefix: true}, expected: "Hé"},
+		{name: "prefix per byte", input: "0x48 0Xc3 0xa9", opts: HexOptions{Prefix: true, Separator: SeparatorSpace}, expected: "Hé"},
+		{name: "lenient whitespace", input: " 48 c3\n\ta9 ", opts: HexOptions{Lenient: true}, expected: "Hé"},
+		{name: "lenient optional separator", input: "48:c3a9", opts: HexOptions{Lenient: true, Separator: SeparatorColon}, expected: "Hé"},
+		{name: "lenient prefixes", input: "0x48 0xc3 a9", opts: HexOptions{Lenient: true}, expected: "Hé"},
+		{name: "space without option", input: "48 c3", expectError: "invalid character ' ' at offset 2"},
+		{name: "wrong separator", input: "48 c3", opts: HexOptions{Separator: SeparatorColon}, expectError: "expected \":\" at offset 2"},
+		{name: "missing prefix", input: "48c3", opts: HexOptions{Prefix: true}, expectError: "expected \"0x\" at offset 0"},
+		{name: "odd length", input: "48c", expectError: "incomplete byte at offset 2"},
+		{name: "trailing separator", input: "48:", opts: HexOptions{Separator: SeparatorColon}, expectError: "incomplete byte at offset 3"},
+		{name: "invalid digit", input: "4g", expectError: "invalid character 'g' at offset 1"},
+		{name: "invalid utf-8", input: "ff", expectError: ErrInvalidDecodedUTF8},
+		{name: "unknown separator", input: "48", opts: HexOptions{Separator: "tab"}, expectError: ErrInvalidOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.DecodeUTF8FromHex(tt.input, tt.opts)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("DecodeUTF8FromHex(%q) error = %v, want %q", tt.input, err, tt.expectError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeUTF8FromHex(%q) unexpected error: %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("DecodeUTF8FromHex(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHexErrors(t *testing.T) {
+	te := &TextEncoding{}
+
+	if _, err := te.EncodeUTF8ToHex("a", HexOptions{Case: "title"}); err == nil {
+		t.Error("EncodeUTF8ToHex() expected error for unknown case")
+	}
+	if _, err := te.EncodeUTF8ToHex(string([]byte{0xFF})); err == nil {
+		t.Error("EncodeUTF8ToHex() expected error for invalid UTF-8 input")
+	}
+	if _, err := te.DecodeUTF8FromHex(strings.Repeat("0", MaxInputSize+1)); err == nil {
+		t.Error("DecodeUTF8FromHex() expected error for oversized input")
+	}
+}
+
+func TestBinaryHexJS(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{
+			name:     "encode uint8array",
+			script:   `encoding.encodeHex(new Uint8Array([0x00, 0xFF, 0x10]))`,
+			expected: "00ff10",
+		},
+		{
+			name:     "encode with options",
+			script:   `encoding.encodeHex(new Uint8Array([0xDE, 0xAD]).buffer, { case: 'upper', separator: 'colon', prefix: true })`,
+			expected: "0xDE:0xAD",
+		},
+		{
+			name:     "encode subarray",
+			script:   `encoding.encodeHex(new Uint8Array([1, 2, 3, 4]).subarray(1, 3), { separator: 'space' })`,
+			expected: "02 03",
+		},
+		{
+			name: "decode to arraybuffer",
+			script: `(() => {
+				const buf = encoding.decodeHex('DE AD be ef', { lenient: true });
+				return (buf instanceof ArrayBuffer) + ':' + Array.from(new Uint8Array(buf)).join(',');
+			})()`,
+			expected: "true:222,173,190,239",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newTestRuntime(t)
+			result := runScript(t, rt, tt.script).String()
+			if result != tt.expected {
+				t.Errorf("%s = %q, want %q", tt.script, result, tt.expected)
+			}
+		})
+	}
+
+	rt := newTestRuntime(t)
+	if _, err := rt.RunString(`encoding.decodeHex('zz')`); err == nil {
+		t.Error("decodeHex() expected error for invalid hex")
+	}
+}
@@ -0,0 +1,189 @@
+package text_encoding
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// LineSegment is the text between two line break opportunities of UAX #14. A line may
+// break after any segment, and must break after one whose MustBreak is set. Offsets are
+// given in UTF-8 bytes and in UTF-16 code units, as in Segment.
+type LineSegment struct {
+	Segment     string `js:"segment"`
+	ByteOffset  int    `js:"byteOffset"`
+	ByteLength  int    `js:"byteLength"`
+	UTF16Offset int    `js:"utf16Offset"`
+	UTF16Length int    `js:"utf16Length"`
+	// Width is the display width of the segment in terminal columns.
+	Width int `js:"width"`
+	// MustBreak is true when the segment ends with a hard line break, such as a line feed.
+	MustBreak bool `js:"mustBreak"`
+}
+
+// lineSegment is the clusters between two line break opportunities.
+type lineSegment struct {
+	clusters  []cluster
+	mustBreak bool
+}
+
+// String returns the text of the segment.
+func (s lineSegment) String() string {
+	var b strings.Builder
+	for _, c := range s.clusters {
+		b.WriteString(c.text)
+	}
+	return b.String()
+}
+
+// lineSegments splits text at the line break opportunities of UAX #14. Opportunities are
+// only taken between grapheme clusters, so a segment never splits one.
+func lineSegments(text string, ambiguous int) []lineSegment {
+	var segments []lineSegment
+	var current lineSegment
+	state := -1
+	for len(text) > 0 {
+		var c string
+		var boundaries int
+		c, text, boundaries, state = uniseg.StepString(text, state)
+		current.clusters = append(current.clusters, cluster{text: c, width: clusterWidth(c, boundaries>>uniseg.ShiftWidth, ambiguous)})
+		lineBreak := boundaries & uniseg.MaskLine
+		if len(text) == 0 || lineBreak != uniseg.LineDontBreak {
+			// The end of the text is always a mandatory break (LB3), but only a real line
+			// break character counts as one here.
+			current.mustBreak = lineBreak == uniseg.LineMustBreak && isLineBreak(c)
+			segments = append(segments, current)
+			current = lineSegment{}
+		}
+	}
+	return segments
+}
+
+// trimTrailing splits clusters into the leading part and the trailing clusters for which
+// trailing returns true.
+func trimTrailing(clusters []cluster, trailing func(string) bool) ([]cluster, []cluster) {
+	i := len(clusters)
+	for i > 0 && trailing(clusters[i-1].text) {
+		i--
+	}
+	return clusters[:i], clusters[i:]
+}
+
+// isLineBreak reports whether c is a hard line break: a line feed, carriage return, CR LF,
+// vertical tab, form feed, next line, line separator or paragraph separator.
+func isLineBreak(c string) bool {
+	return uniseg.HasTrailingLineBreakInString(c)
+}
+
+// isSpace reports whether c is a space or a tab, which hang at the end of a wrapped line.
+func isSpace(c string) bool {
+	return c == " " || c == "\t"
+}
+
+// SegmentLineBreaks splits text at the line break opportunities of UAX #14, the places
+// where a line may wrap. Opportunities inside a grapheme cluster are never taken.
+func (TextEncoding) SegmentLineBreaks(text string, opts ...WidthOptions) ([]LineSegment, error) {
+	ambiguous, err := widthOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateText(text); err != nil {
+		return nil, err
+	}
+	out := []LineSegment{}
+	byteOffset, utf16Offset := 0, 0
+	for _, segment := range lineSegments(text, ambiguous) {
+		s := segment.String()
+		ls := LineSegment{
+			Segment:     s,
+			ByteOffset:  byteOffset,
+			ByteLength:  len(s),
+			UTF16Offset: utf16Offset,
+			UTF16Length: utf16Length(s),
+			Width:       clustersWidth(segment.clusters),
+			MustBreak:   segment.mustBreak,
+		}
+		out = append(out, ls)
+		byteOffset += ls.ByteLength
+		utf16Offset += ls.UTF16Length
+	}
+	return out, nil
+}
+
+// Wrap breaks text into lines of at most width terminal columns, in which East Asian wide
+// and fullwidth characters take two columns and combining marks none. Lines break at the
+// opportunities of UAX #14 and at every hard line break, which is removed. Spaces at the
+// end of a line are dropped. A word wider than a line is broken between grapheme clusters, and a
+// single cluster wider than a line is put on a line of its own. Widths are measured as by
+// DisplayWidth.
+func (TextEncoding) Wrap(text string, width int, opts ...WidthOptions) ([]string, error) {
+	if width < 1 {
+		return nil, fmt.Errorf("%s: width must be at least 1, got %d", ErrInvalidOption, width)
+	}
+	ambiguous, err := widthOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateText(text); err != nil {
+		return nil, err
+	}
+	w := wrapper{width: width}
+	for _, segment := range lineSegments(text, ambiguous) {
+		clusters := segment.clusters
+		if segment.mustBreak {
+			clusters, _ = trimTrailing(clusters, isLineBreak)
+		}
+		w.add(clusters)
+		if segment.mustBreak {
+			w.wrap()
+		}
+	}
+	w.wrap()
+	return w.lines, nil
+}
+
+// wrapper collects the lines of Wrap.
+type wrapper struct {
+	width     int
+	lines     []string
+	line      strings.Builder
+	lineWidth int
+}
+
+// add appends the clusters of one line segment, wrapping first if they do not fit.
+func (w *wrapper) add(clusters []cluster) {
+	word, spaces := trimTrailing(clusters, isSpace)
+	wordWidth := clustersWidth(word)
+	if w.lineWidth > 0 && w.lineWidth+wordWidth > w.width {
+		w.wrap()
+	}
+	if wordWidth <= w.width-w.lineWidth {
+		w.write(word)
+	} else {
+		for _, c := range word {
+			if w.lineWidth > 0 && w.lineWidth+c.width > w.width {
+				w.wrap()
+			}
+			w.write([]cluster{c})
+		}
+	}
+	// Trailing spaces hang past the end of the line and are dropped if it wraps.
+	w.write(spaces)
+}
+
+// write appends clusters to the current line.
+func (w *wrapper) write(clusters []cluster) {
+	for _, c := range clusters {
+		w.line.WriteString(c.text)
+		w.lineWidth += c.width
+	}
+}
+
+// wrap ends the current line, dropping its trailing spaces. They are dropped at a hard
+// break as well as at a soft one, since they may be wider than the line.
+func (w *wrapper) wrap() {
+	w.lines = append(w.lines, strings.TrimRight(w.line.String(), " \t"))
+	w.line.Reset()
+	w.lineWidth = 0
+}
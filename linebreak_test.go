@@ -0,0 +1,217 @@
+package text_encoding
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSegmentLineBreaks(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		segments []string
+		widths   []int
+		hard     []bool
+	}{
+		{
+			name:     "spaces",
+			input:    "The quick fox",
+			segments: []string{"The ", "quick ", "fox"},
+			widths:   []int{4, 6, 3},
+			hard:     []bool{false, false, false},
+		},
+		{
+			name:     "hard breaks",
+			input:    "a\r\nb\n\nc",
+			segments: []string{"a\r\n", "b\n", "\n", "c"},
+			widths:   []int{1, 1, 0, 1},
+			hard:     []bool{true, true, true, false},
+		},
+		{
+			name:     "ideographs",
+			input:    "你好，世界。",
+			segments: []string{"你", "好，", "世", "界。"},
+			widths:   []int{2, 4, 2, 4},
+			hard:     []bool{false, false, false, false},
+		},
+		{
+			name:     "hyphen and punctuation",
+			input:    "well-known (yes)",
+			segments: []string{"well-", "known ", "(yes)"},
+			widths:   []int{5, 6, 5},
+			hard:     []bool{false, false, false},
+		},
+		{
+			name:     "no break before closing punctuation",
+			input:    "price: $10.00",
+			segments: []string{"price: ", "$10.00"},
+			widths:   []int{7, 6},
+			hard:     []bool{false, false},
+		},
+		{
+			name:     "emoji",
+			input:    "👨‍👩‍👧‍👦👍🏽",
+			segments: []string{"👨‍👩‍👧‍👦", "👍🏽"},
+			widths:   []int{2, 2},
+			hard:     []bool{false, false},
+		},
+		{name: "empty", input: "", segments: []string{}, widths: []int{}, hard: []bool{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := te.SegmentLineBreaks(tt.input)
+			if err != nil {
+				t.Fatalf("SegmentLineBreaks() unexpected error: %v", err)
+			}
+			texts, widths, hard := []string{}, []int{}, []bool{}
+			offset := 0
+			for _, s := range segments {
+				texts = append(texts, s.Segment)
+				widths = append(widths, s.Width)
+				hard = append(hard, s.MustBreak)
+				if s.ByteOffset != offset || s.ByteLength != len(s.Segment) {
+					t.Errorf("SegmentLineBreaks(%q) segment %q at %d+%d, want %d", tt.input, s.Segment, s.ByteOffset, s.ByteLength, offset)
+				}
+				offset += s.ByteLength
+			}
+			if !reflect.DeepEqual(texts, tt.segments) {
+				t.Errorf("SegmentLineBreaks(%q) = %q, want %q", tt.input, texts, tt.segments)
+			}
+			if !reflect.DeepEqual(widths, tt.widths) {
+				t.Errorf("SegmentLineBreaks(%q) widths = %v, want %v", tt.input, widths, tt.widths)
+			}
+			if !reflect.DeepEqual(hard, tt.hard) {
+				t.Errorf("SegmentLineBreaks(%q) mustBreak = %v, want %v", tt.input, hard, tt.hard)
+			}
+		})
+	}
+
+	segments, err := te.SegmentLineBreaks("😀 x")
+	if err != nil {
+		t.Fatalf("SegmentLineBreaks() unexpected error: %v", err)
+	}
+	if len(segments) != 2 || segments[1].UTF16Offset != 3 || segments[1].ByteOffset != 5 {
+		t.Errorf("SegmentLineBreaks() = %+v, want the second segment at UTF-16 offset 3, byte 5", segments)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		expected []string
+	}{
+		{
+			name:     "words",
+			input:    "The quick brown fox jumps over the lazy dog",
+			width:    10,
+			expected: []string{"The quick", "brown fox", "jumps over", "the lazy", "dog"},
+		},
+		{
+			name:     "exact fit",
+			input:    "abc def",
+			width:    7,
+			expected: []string{"abc def"},
+		},
+		{
+			name:     "trailing spaces hang",
+			input:    "abc   def",
+			width:    3,
+			expected: []string{"abc", "def"},
+		},
+		{
+			name:     "wide characters",
+			input:    "你好世界，欢迎光临",
+			width:    6,
+			expected: []string{"你好世", "界，欢", "迎光临"},
+		},
+		{
+			name:     "combining marks take no width",
+			input:    "cafe\u0301 cafe\u0301",
+			width:    4,
+			expected: []string{"cafe\u0301", "cafe\u0301"},
+		},
+		{
+			name:     "long word",
+			input:    "supercalifragilistic!",
+			width:    8,
+			expected: []string{"supercal", "ifragili", "stic!"},
+		},
+		{
+			name:     "clusters are not split",
+			input:    "👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦",
+			width:    5,
+			expected: []string{"👨‍👩‍👧‍👦👨‍👩‍👧‍👦", "👨‍👩‍👧‍👦"},
+		},
+		{
+			name:     "cluster wider than a line",
+			input:    "a你b",
+			width:    1,
+			expected: []string{"a", "你", "b"},
+		},
+		{
+			name:     "hard breaks",
+			input:    "one two\r\n\nthree four\n",
+			width:    7,
+			expected: []string{"one two", "", "three", "four", ""},
+		},
+		{
+			name:     "spaces before a hard break",
+			input:    "abc      \nx\t \r\ny",
+			width:    4,
+			expected: []string{"abc", "x", "y"},
+		},
+		{name: "empty", input: "", width: 3, expected: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := te.Wrap(tt.input, tt.width)
+			if err != nil {
+				t.Fatalf("Wrap() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(lines, tt.expected) {
+				t.Errorf("Wrap(%q, %d) = %q, want %q", tt.input, tt.width, lines, tt.expected)
+			}
+			for _, line := range lines {
+				// Only a single cluster wider than the line may exceed it.
+				if width, _ := te.DisplayWidth(line); width > tt.width && len(measureClusters(line, 1)) > 1 {
+					t.Errorf("Wrap(%q, %d) line %q is %d columns wide", tt.input, tt.width, line, width)
+				}
+			}
+		})
+	}
+
+	if _, err := te.Wrap("abc", 0); err == nil || !strings.Contains(err.Error(), ErrInvalidOption) {
+		t.Errorf("Wrap() error = %v, want %q", err, ErrInvalidOption)
+	}
+	if _, err := te.Wrap("\xff", 3); err == nil || !strings.Contains(err.Error(), ErrInvalidUTF8) {
+		t.Errorf("Wrap() error = %v, want %q", err, ErrInvalidUTF8)
+	}
+}
+
+func TestLineBreakJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const receipt = 'Espresso doppio x2 合計 ¥1,200';
+		const lines = encoding.wrap(receipt, 12);
+		const segments = encoding.segmentLineBreaks('one two\nthree');
+		return [
+			JSON.stringify(lines),
+			segments.map((s) => s.segment + (s.mustBreak ? '!' : '')).join('|'),
+			segments[2].utf16Offset,
+		].join(' / ');
+	})()`).String()
+	expected := `["Espresso","doppio x2 合","計 ¥1,200"] / one |two` + "\n" + `!|three / 8`
+	if result != expected {
+		t.Errorf("line breaking = %q, want %q", result, expected)
+	}
+}
@@ -0,0 +1,190 @@
+package text_encoding
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Unicode normalization forms accepted by the normalization functions.
+// The names are case-insensitive and an empty form means NFC, as in String.prototype.normalize.
+const (
+	NormNFC  = "NFC"
+	NormNFD  = "NFD"
+	NormNFKC = "NFKC"
+	NormNFKD = "NFKD"
+)
+
+// Results of QuickCheckNormalization, as defined by UAX #15.
+const (
+	QuickCheckYes   = "yes"
+	QuickCheckNo    = "no"
+	QuickCheckMaybe = "maybe"
+)
+
+// Hangul syllables and conjoining jamo, which the norm tables handle algorithmically.
+const (
+	hangulFirst  = 0xAC00
+	hangulLast   = 0xD7A3
+	jamoVFirst   = 0x1161
+	jamoVLast    = 0x1175
+	jamoTFirst   = 0x11A8
+	jamoTLast    = 0x11C2
+	maxCodePoint = 0x10FFFF
+)
+
+var (
+	combinesBackwardOnce sync.Once
+	combinesBackwardSet  map[rune]bool
+)
+
+// normForm resolves a normalization form name.
+func normForm(form string) (norm.Form, error) {
+	switch strings.ToUpper(form) {
+	case "", NormNFC:
+		return norm.NFC, nil
+	case NormNFD:
+		return norm.NFD, nil
+	case NormNFKC:
+		return norm.NFKC, nil
+	case NormNFKD:
+		return norm.NFKD, nil
+	}
+	return 0, fmt.Errorf("%s: form must be %q, %q, %q or %q, got %q", ErrInvalidOption, NormNFC, NormNFD, NormNFKC, NormNFKD, form)
+}
+
+// normInput validates text and resolves form for the normalization functions.
+func normInput(text, form string) (norm.Form, error) {
+	f, err := normForm(form)
+	if err != nil {
+		return 0, err
+	}
+	if err := validateInputSize(len(text)); err != nil {
+		return 0, err
+	}
+	if err := validateUTF8String(text); err != nil {
+		return 0, err
+	}
+	return f, nil
+}
+
+// Normalize returns text in the given normalization form, NFC by default.
+// Composed and decomposed spellings of the same text, such as "\u00e9" and "e\u0301",
+// normalize to the same string and so give the same rune and byte counts.
+func (TextEncoding) Normalize(text string, form string) (string, error) {
+	f, err := normInput(text, form)
+	if err != nil {
+		return "", err
+	}
+	return f.String(text), nil
+}
+
+// IsNormalized reports whether text is already in the given normalization form.
+func (TextEncoding) IsNormalized(text string, form string) (bool, error) {
+	f, err := normInput(text, form)
+	if err != nil {
+		return false, err
+	}
+	return f.IsNormalString(text), nil
+}
+
+// EqualNormalized reports whether a and b are equal once both are normalized to the
+// given form. Under NFC or NFD this is canonical equivalence; under NFKC or NFKD it is
+// compatibility equivalence, so "ﬁ" equals "fi".
+func (TextEncoding) EqualNormalized(a, b string, form string) (bool, error) {
+	f, err := normInput(a, form)
+	if err != nil {
+		return false, err
+	}
+	if _, err := normInput(b, form); err != nil {
+		return false, err
+	}
+	if a == b {
+		return true, nil
+	}
+	return f.String(a) == f.String(b), nil
+}
+
+// QuickCheckNormalization runs the UAX #15 quick check of text against the given form
+// without normalizing it. It returns "yes" when text is certainly normalized, "no" when it
+// certainly is not, and "maybe" when only IsNormalized can tell.
+func (TextEncoding) QuickCheckNormalization(text string, form string) (string, error) {
+	f, err := normInput(text, form)
+	if err != nil {
+		return "", err
+	}
+	result := QuickCheckYes
+	var lastCCC uint8
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		props := f.PropertiesString(text[i:])
+		ccc := props.CCC()
+		if ccc != 0 && lastCCC > ccc {
+			return QuickCheckNo, nil
+		}
+		switch quickCheckRune(f, r, props) {
+		case QuickCheckNo:
+			return QuickCheckNo, nil
+		case QuickCheckMaybe:
+			result = QuickCheckMaybe
+		}
+		lastCCC = ccc
+		i += size
+	}
+	return result, nil
+}
+
+// quickCheckRune returns the quick check property of r in form f. The norm package keeps
+// these flags private, so they are derived from the decompositions it exposes: a rune is
+// "no" when normalizing it on its own changes it, and "maybe" in the composing forms when
+// it can combine with the rune before it.
+func quickCheckRune(f norm.Form, r rune, props norm.Properties) string {
+	composing := f == norm.NFC || f == norm.NFKC
+	if r >= hangulFirst && r <= hangulLast {
+		if composing {
+			return QuickCheckYes
+		}
+		return QuickCheckNo
+	}
+	if props.Decomposition() != nil {
+		if !composing {
+			return QuickCheckNo
+		}
+		s := string(r)
+		if f.String(s) != s {
+			return QuickCheckNo
+		}
+	}
+	if composing && combinesBackward(r) {
+		return QuickCheckMaybe
+	}
+	return QuickCheckYes
+}
+
+// combinesBackward reports whether r is the trailing rune of some primary composite, or a
+// Hangul vowel or trailing consonant, and so can compose with the rune before it.
+func combinesBackward(r rune) bool {
+	if (r >= jamoVFirst && r <= jamoVLast) || (r >= jamoTFirst && r <= jamoTLast) {
+		return true
+	}
+	combinesBackwardOnce.Do(func() {
+		combinesBackwardSet = make(map[rune]bool)
+		var buf [utf8.UTFMax]byte
+		for c := rune(0); c <= maxCodePoint; c++ {
+			if !utf8.ValidRune(c) || (c >= hangulFirst && c <= hangulLast) {
+				continue
+			}
+			n := utf8.EncodeRune(buf[:], c)
+			decomposition := norm.NFD.Properties(buf[:n]).Decomposition()
+			if decomposition == nil || !norm.NFC.IsNormal(buf[:n]) {
+				continue
+			}
+			last, _ := utf8.DecodeLastRune(decomposition)
+			combinesBackwardSet[last] = true
+		}
+	})
+	return combinesBackwardSet[r]
+}
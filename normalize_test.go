@@ -0,0 +1,187 @@
+package text_encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		form     string
+		expected string
+	}{
+		{name: "default composes", input: "cafe\u0301", expected: "café"},
+		{name: "nfc", input: "e\u0301", form: "NFC", expected: "é"},
+		{name: "nfd", input: "é", form: "NFD", expected: "e\u0301"},
+		{name: "lower case form", input: "é", form: "nfd", expected: "e\u0301"},
+		{name: "nfkc ligature", input: "ﬁle", form: "NFKC", expected: "file"},
+		{name: "nfc keeps ligature", input: "ﬁle", form: "NFC", expected: "ﬁle"},
+		{name: "nfkd", input: "①é", form: "NFKD", expected: "1e\u0301"},
+		{name: "hangul", input: "\u1100\u1161\u11A8", form: "NFC", expected: "각"},
+		{name: "reorders marks", input: "a\u0301\u0316", form: "NFD", expected: "a\u0316\u0301"},
+		{name: "empty", input: "", form: "NFKD", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.Normalize(tt.input, tt.form)
+			if err != nil {
+				t.Fatalf("Normalize() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Normalize(%q, %q) = %q, want %q", tt.input, tt.form, result, tt.expected)
+			}
+			normalized, err := te.IsNormalized(result, tt.form)
+			if err != nil {
+				t.Fatalf("IsNormalized() unexpected error: %v", err)
+			}
+			if !normalized {
+				t.Errorf("IsNormalized(%q, %q) = false, want true", result, tt.form)
+			}
+		})
+	}
+
+	if _, err := te.Normalize("abc", "NFX"); err == nil || !strings.Contains(err.Error(), ErrInvalidOption) {
+		t.Errorf("Normalize() error = %v, want %q", err, ErrInvalidOption)
+	}
+	if _, err := te.IsNormalized("\xff", "NFC"); err == nil || !strings.Contains(err.Error(), ErrInvalidUTF8) {
+		t.Errorf("IsNormalized() error = %v, want %q", err, ErrInvalidUTF8)
+	}
+}
+
+func TestNormalizeCounts(t *testing.T) {
+	te := &TextEncoding{}
+
+	composed, err := te.Normalize("e\u0301", NormNFC)
+	if err != nil {
+		t.Fatalf("Normalize() unexpected error: %v", err)
+	}
+	runes, _ := te.CountUTF8Runes(composed)
+	bytes, _ := te.CountUTF8Bytes(composed)
+	if runes != 1 || bytes != 2 {
+		t.Errorf("NFC counts = %d runes, %d bytes, want 1, 2", runes, bytes)
+	}
+
+	decomposed, err := te.Normalize("é", NormNFD)
+	if err != nil {
+		t.Fatalf("Normalize() unexpected error: %v", err)
+	}
+	runes, _ = te.CountUTF8Runes(decomposed)
+	bytes, _ = te.CountUTF8Bytes(decomposed)
+	if runes != 2 || bytes != 3 {
+		t.Errorf("NFD counts = %d runes, %d bytes, want 2, 3", runes, bytes)
+	}
+}
+
+func TestEqualNormalized(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		form     string
+		expected bool
+	}{
+		{name: "canonical", a: "é", b: "e\u0301", expected: true},
+		{name: "canonical nfd", a: "é", b: "e\u0301", form: "NFD", expected: true},
+		{name: "angstrom", a: "\u212B", b: "Å", form: "NFC", expected: true},
+		{name: "compatibility under nfc", a: "ﬁ", b: "fi", form: "NFC", expected: false},
+		{name: "compatibility under nfkc", a: "ﬁ", b: "fi", form: "NFKC", expected: true},
+		{name: "different", a: "e", b: "é", expected: false},
+		{name: "mark order", a: "a\u0301\u0316", b: "a\u0316\u0301", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.EqualNormalized(tt.a, tt.b, tt.form)
+			if err != nil {
+				t.Fatalf("EqualNormalized() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("EqualNormalized(%q, %q, %q) = %v, want %v", tt.a, tt.b, tt.form, result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := te.EqualNormalized("a", "\xff", "NFC"); err == nil {
+		t.Error("EqualNormalized() expected error for invalid UTF-8")
+	}
+}
+
+func TestQuickCheckNormalization(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		form     string
+		expected string
+	}{
+		{name: "ascii", input: "hello", form: "NFC", expected: QuickCheckYes},
+		{name: "composed nfc", input: "café", form: "NFC", expected: QuickCheckYes},
+		{name: "composed nfd", input: "café", form: "NFD", expected: QuickCheckNo},
+		{name: "combining acute nfc", input: "cafe\u0301", form: "NFC", expected: QuickCheckMaybe},
+		{name: "combining acute nfd", input: "cafe\u0301", form: "NFD", expected: QuickCheckYes},
+		{name: "non-composing mark", input: "a\u0316", form: "NFC", expected: QuickCheckYes},
+		{name: "singleton", input: "\u212B", form: "NFC", expected: QuickCheckNo},
+		{name: "composition exclusion", input: "\u0958", form: "NFC", expected: QuickCheckNo},
+		{name: "mark order", input: "a\u0301\u0316", form: "NFD", expected: QuickCheckNo},
+		{name: "hangul syllable", input: "각", form: "NFC", expected: QuickCheckYes},
+		{name: "hangul syllable nfd", input: "각", form: "NFD", expected: QuickCheckNo},
+		{name: "hangul vowel", input: "\u1100\u1161", form: "NFC", expected: QuickCheckMaybe},
+		{name: "ligature nfc", input: "ﬁ", form: "NFC", expected: QuickCheckYes},
+		{name: "ligature nfkc", input: "ﬁ", form: "NFKC", expected: QuickCheckNo},
+		{name: "empty", input: "", form: "NFKD", expected: QuickCheckYes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.QuickCheckNormalization(tt.input, tt.form)
+			if err != nil {
+				t.Fatalf("QuickCheckNormalization() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("QuickCheckNormalization(%q, %q) = %q, want %q", tt.input, tt.form, result, tt.expected)
+			}
+			normalized, err := te.IsNormalized(tt.input, tt.form)
+			if err != nil {
+				t.Fatalf("IsNormalized() unexpected error: %v", err)
+			}
+			if (result == QuickCheckYes && !normalized) || (result == QuickCheckNo && normalized) {
+				t.Errorf("IsNormalized(%q, %q) = %v, contradicts quick check %q", tt.input, tt.form, normalized, result)
+			}
+		})
+	}
+}
+
+func TestNormalizeJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const composed = 'café';
+		const decomposed = 'cafe\u0301';
+		return [
+			encoding.normalize(decomposed) === composed,
+			encoding.normalize(composed, 'NFD') === composed.normalize('NFD'),
+			encoding.normalize('ﬁ', 'NFKC'),
+			encoding.isNormalized(decomposed, 'NFC'),
+			encoding.isNormalized(decomposed, 'NFD'),
+			encoding.equalNormalized(composed, decomposed, 'NFC'),
+			encoding.quickCheckNormalization(decomposed, 'NFC'),
+			encoding.countUTF8Bytes(encoding.normalize(decomposed, 'NFC')),
+		].join('|');
+	})()`).String()
+	expected := "true|true|fi|false|true|true|maybe|5"
+	if result != expected {
+		t.Errorf("normalization = %q, want %q", result, expected)
+	}
+
+	if _, err := rt.RunString(`encoding.normalize('x', 'NFQ')`); err == nil {
+		t.Error("normalize() expected error for an unknown form")
+	}
+}
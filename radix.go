@@ -0,0 +1,259 @@
+package text_encoding
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// ErrInvalidRadix is returned when radix-encoded input cannot be decoded.
+const ErrInvalidRadix = "failed to decode radix"
+
+// Named alphabets for RadixOptions.Alphabet.
+const (
+	// Radix36 is digits followed by lower case letters, as Number.prototype.toString(36)
+	// writes them. Upper case letters are accepted when decoding.
+	Radix36 = "base36"
+	// Radix62 is digits, then lower case and upper case letters. It is the default.
+	Radix62 = "base62"
+)
+
+var (
+	base36Alphabet = foldCase(mustRadixAlphabet("0123456789abcdefghijklmnopqrstuvwxyz"))
+	base62Alphabet = mustRadixAlphabet("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+)
+
+// maxSafeInteger is Number.MAX_SAFE_INTEGER, the largest integer a Number holds exactly.
+const maxSafeInteger = 1<<53 - 1
+
+// RadixOptions configures the arbitrary-radix encoders and decoders.
+// Alphabet is either a named alphabet or the digits of a custom one, in order of value;
+// its length is the radix.
+type RadixOptions struct {
+	Alphabet string `js:"alphabet"`
+}
+
+// foldCase makes the upper case letters of an all lower case alphabet decode to the
+// same values as the lower case ones.
+func foldCase(a *radixAlphabet) *radixAlphabet {
+	for c := 'a'; c <= 'z'; c++ {
+		a.values[c-'a'+'A'] = a.values[c]
+	}
+	return a
+}
+
+// radixOptions returns the first of opts, or the defaults if there is none.
+func radixOptions(opts []RadixOptions) RadixOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return RadixOptions{}
+}
+
+// alphabet resolves the Alphabet option. Names are matched in any case and take
+// precedence over custom digits.
+func (o RadixOptions) alphabet() (*radixAlphabet, error) {
+	switch strings.ToLower(o.Alphabet) {
+	case "", Radix62:
+		return base62Alphabet, nil
+	case Radix36:
+		return base36Alphabet, nil
+	}
+	return newRadixAlphabet(o.Alphabet)
+}
+
+// encode converts data to the configured radix, keeping each leading zero byte as a
+// leading zero digit.
+func (o RadixOptions) encode(data []byte) (string, error) {
+	alphabet, err := o.alphabet()
+	if err != nil {
+		return "", err
+	}
+	return alphabet.encode(data), nil
+}
+
+// decode reads a string written by encode back into bytes.
+func (o RadixOptions) decode(encoded string) ([]byte, error) {
+	alphabet, err := o.alphabet()
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := alphabet.decode(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrInvalidRadix, err)
+	}
+	return decoded, nil
+}
+
+// bigIntFromValue converts a BigInt, a safe integer Number or a decimal string to a
+// non-negative *big.Int.
+func bigIntFromValue(v sobek.Value) (*big.Int, error) {
+	if common.IsNullish(v) {
+		return nil, errors.New(ErrNilInput)
+	}
+
+	var n *big.Int
+	switch value := v.Export().(type) {
+	case *big.Int:
+		n = value
+	case int64:
+		if value > maxSafeInteger || value < -maxSafeInteger {
+			return nil, fmt.Errorf("%s: number %d is not a safe integer, use a BigInt", ErrInvalidOption, value)
+		}
+		n = big.NewInt(value)
+	case float64:
+		if value != math.Trunc(value) || math.Abs(value) > maxSafeInteger {
+			return nil, fmt.Errorf("%s: number %v is not a safe integer, use a BigInt", ErrInvalidOption, value)
+		}
+		n = big.NewInt(int64(value))
+	case string:
+		var ok bool
+		if n, ok = new(big.Int).SetString(value, 10); !ok {
+			return nil, fmt.Errorf("%s: %q is not a decimal integer", ErrInvalidOption, value)
+		}
+	default:
+		return nil, fmt.Errorf("%s: expected a BigInt, number or decimal string, got %s", ErrInvalidOption, v.ExportType())
+	}
+	if n.Sign() < 0 {
+		return nil, fmt.Errorf("%s: integer must not be negative, got %s", ErrInvalidOption, n)
+	}
+	return n, nil
+}
+
+// encodeInteger writes n in the alphabet's radix without leading zeros.
+func (a *radixAlphabet) encodeInteger(n *big.Int) string {
+	if n.Sign() == 0 {
+		return a.digits[:1]
+	}
+	return a.encode(n.Bytes())
+}
+
+// decodeInteger reads a number written by encodeInteger. Leading zero digits are allowed.
+func (a *radixAlphabet) decodeInteger(encoded string) (*big.Int, error) {
+	if encoded == "" {
+		return nil, fmt.Errorf("%s: %s", ErrInvalidRadix, ErrEmptyInput)
+	}
+	decoded, err := a.decode(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrInvalidRadix, err)
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
+
+// EncodeRadix converts the bytes of an ArrayBuffer, TypedArray or DataView to a
+// big-endian number written with a custom alphabet, or the named "base36" or "base62"
+// (default) alphabet. Leading zero bytes are kept as leading zero digits, so the bytes
+// survive a round trip.
+func (TextEncoding) EncodeRadix(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o RadixOptions
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encode(data)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encoded)
+}
+
+// DecodeRadix decodes a string written by EncodeRadix to an ArrayBuffer.
+// It throws an error with the offset of the first character outside the alphabet.
+func (TextEncoding) DecodeRadix(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o RadixOptions
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	decoded, err := o.decode(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(decoded))
+}
+
+// EncodeUTF8ToRadix converts a string to UTF-8 bytes and then to the configured radix.
+func (TextEncoding) EncodeUTF8ToRadix(text string, opts ...RadixOptions) (string, error) {
+	if err := validateInputSize(len(text)); err != nil {
+		return "", err
+	}
+	if err := validateUTF8String(text); err != nil {
+		return "", err
+	}
+	return radixOptions(opts).encode([]byte(text))
+}
+
+// DecodeUTF8FromRadix decodes a string written by EncodeUTF8ToRadix to UTF-8 text.
+// It validates both the encoding and the resulting UTF-8.
+func (TextEncoding) DecodeUTF8FromRadix(encoded string, opts ...RadixOptions) (string, error) {
+	if err := validateInputSize(len(encoded)); err != nil {
+		return "", err
+	}
+	decoded, err := radixOptions(opts).decode(encoded)
+	if err != nil {
+		return "", err
+	}
+	if err := validateUTF8Bytes(decoded); err != nil {
+		return "", errors.New(ErrInvalidDecodedUTF8)
+	}
+	return string(decoded), nil
+}
+
+// EncodeRadixInteger writes a non-negative integer, given as a BigInt, a safe integer
+// Number or a decimal string, in the configured radix. Zero is written as the zero digit.
+func (TextEncoding) EncodeRadixInteger(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	n, err := bigIntFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	var o RadixOptions
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	alphabet, err := o.alphabet()
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(alphabet.encodeInteger(n))
+}
+
+// DecodeRadixInteger reads an integer written by EncodeRadixInteger and returns it as a BigInt.
+func (TextEncoding) DecodeRadixInteger(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	var o RadixOptions
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	alphabet, err := o.alphabet()
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	n, err := alphabet.decodeInteger(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(n)
+}
@@ -0,0 +1,166 @@
+package text_encoding
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRadix(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		opts     RadixOptions
+		expected string
+	}{
+		{name: "empty", input: []byte{}, expected: ""},
+		{name: "base62", input: []byte("Hello World"), expected: "73xPuGYmWKgR29m"},
+		{name: "base62 leading zeros", input: []byte{0x00, 0x00, 0x01, 0x02}, expected: "004a"},
+		{name: "only zeros", input: []byte{0x00, 0x00}, expected: "00"},
+		{name: "base36", input: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, opts: RadixOptions{Alphabet: Radix36}, expected: "3w5e11264sgsg"},
+		{name: "name in any case", input: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, opts: RadixOptions{Alphabet: "Base36"}, expected: "3w5e11264sgsg"},
+		{name: "base62 in upper case", input: []byte{0x00, 0x00, 0x01, 0x02}, opts: RadixOptions{Alphabet: "BASE62"}, expected: "004a"},
+		{name: "binary", input: []byte("hi"), opts: RadixOptions{Alphabet: "01"}, expected: "110100001101001"},
+		{name: "custom leading zeros", input: []byte{0x00, 0x05}, opts: RadixOptions{Alphabet: "xyz"}, expected: "xyz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.opts.encode(tt.input)
+			if err != nil {
+				t.Fatalf("encode() unexpected error: %v", err)
+			}
+			if encoded != tt.expected {
+				t.Errorf("encode(% X) = %q, want %q", tt.input, encoded, tt.expected)
+			}
+			decoded, err := tt.opts.decode(tt.expected)
+			if err != nil {
+				t.Fatalf("decode(%q) unexpected error: %v", tt.expected, err)
+			}
+			if !bytes.Equal(decoded, tt.input) {
+				t.Errorf("decode(%q) = % X, want % X", tt.expected, decoded, tt.input)
+			}
+		})
+	}
+}
+
+func TestRadixErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     RadixOptions
+		expected string
+	}{
+		{name: "outside base62", input: "73x-uG", expected: ErrInvalidRadix + ": invalid character '-' at offset 3"},
+		{name: "upper case base36", input: "3W5E11264SGSG", opts: RadixOptions{Alphabet: Radix36}},
+		{name: "upper case custom", input: "AB", opts: RadixOptions{Alphabet: "0123456789ab"}, expected: "invalid character 'A' at offset 0"},
+		{name: "duplicate", input: "01", opts: RadixOptions{Alphabet: "0120"}, expected: ErrInvalidAlphabet + ": duplicate character '0' at offset 3"},
+		{name: "too short", input: "0", opts: RadixOptions{Alphabet: "0"}, expected: ErrInvalidAlphabet},
+		{name: "non-ascii", input: "0", opts: RadixOptions{Alphabet: "01é"}, expected: ErrInvalidAlphabet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.opts.decode(tt.input)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("decode(%q) unexpected error: %v", tt.input, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("decode(%q) error = %v, want %q", tt.input, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRadixUTF8(t *testing.T) {
+	te := &TextEncoding{}
+
+	encoded, err := te.EncodeUTF8ToRadix("Hello World")
+	if err != nil {
+		t.Fatalf("EncodeUTF8ToRadix() unexpected error: %v", err)
+	}
+	if encoded != "73xPuGYmWKgR29m" {
+		t.Errorf("EncodeUTF8ToRadix() = %q, want %q", encoded, "73xPuGYmWKgR29m")
+	}
+	decoded, err := te.DecodeUTF8FromRadix(encoded)
+	if err != nil {
+		t.Fatalf("DecodeUTF8FromRadix() unexpected error: %v", err)
+	}
+	if decoded != "Hello World" {
+		t.Errorf("DecodeUTF8FromRadix() = %q, want %q", decoded, "Hello World")
+	}
+
+	if _, err := te.EncodeUTF8ToRadix(string([]byte{0xFF})); err == nil {
+		t.Error("EncodeUTF8ToRadix() expected error for invalid UTF-8 input")
+	}
+	if _, err := te.DecodeUTF8FromRadix("47", RadixOptions{Alphabet: Radix36}); err == nil || err.Error() != ErrInvalidDecodedUTF8 {
+		t.Errorf("DecodeUTF8FromRadix() error = %v, want %q", err, ErrInvalidDecodedUTF8)
+	}
+}
+
+func TestRadixIntegerJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const id = encoding.encodeRadixInteger(12345);
+		const big = encoding.encodeRadixInteger(2n ** 64n, { alphabet: 'base36' });
+		const fromString = encoding.encodeRadixInteger('255', { alphabet: 'base36' });
+		const zero = encoding.encodeRadixInteger(0n, { alphabet: 'xyz' });
+		const back = encoding.decodeRadixInteger('3W5E11264SGSG', { alphabet: 'base36' });
+		const padded = encoding.decodeRadixInteger('0003d7');
+		return [id, big, fromString, zero, typeof back, back === 2n ** 64n, padded].join('|');
+	})()`).String()
+	expected := "3d7|3w5e11264sgsg|73|x|bigint|true|12345"
+	if result != expected {
+		t.Errorf("radix integers = %q, want %q", result, expected)
+	}
+
+	for _, script := range []string{
+		`encoding.encodeRadixInteger(-1)`,
+		`encoding.encodeRadixInteger(1.5)`,
+		`encoding.encodeRadixInteger('12ab')`,
+		`encoding.encodeRadixInteger(2 ** 60)`,
+		`encoding.encodeRadixInteger(Number.MAX_SAFE_INTEGER + 1)`,
+		`encoding.decodeRadixInteger('')`,
+		`encoding.decodeRadixInteger('12', { alphabet: 'aa' })`,
+	} {
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected error", script)
+		}
+	}
+}
+
+func TestRadixJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const encoded = encoding.encodeRadix(new Uint8Array([0, 0, 1, 2]));
+		const decoded = encoding.decodeRadix(encoded);
+		const text = encoding.encodeUTF8ToRadix('hi', { alphabet: '01' });
+		return [
+			encoded,
+			decoded instanceof ArrayBuffer,
+			new Uint8Array(decoded).join(','),
+			text,
+			encoding.decodeUTF8FromRadix(text, { alphabet: '01' }),
+		].join('|');
+	})()`).String()
+	expected := "004a|true|0,0,1,2|110100001101001|hi"
+	if result != expected {
+		t.Errorf("radix = %q, want %q", result, expected)
+	}
+
+	throwing := []string{
+		`encoding.encodeRadix('abc')`,
+		`encoding.encodeRadix([1, 2, 3])`,
+		`encoding.decodeRadix(null)`,
+	}
+	for _, script := range throwing {
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected to throw", script)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package text_encoding
+
+import (
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/rivo/uniseg"
+)
+
+// Segment is one word or sentence of a segmented text. Offsets are given both in UTF-8
+// bytes, as CountUTF8Bytes counts them, and in UTF-16 code units, as JavaScript string
+// indexes count them, so text.slice(utf16Offset, utf16Offset + utf16Length) is the segment.
+type Segment struct {
+	Segment     string `js:"segment"`
+	ByteOffset  int    `js:"byteOffset"`
+	ByteLength  int    `js:"byteLength"`
+	UTF16Offset int    `js:"utf16Offset"`
+	UTF16Length int    `js:"utf16Length"`
+	// IsWordLike is true for words that hold a letter or a number, and false for spaces,
+	// punctuation and emoji. It is always false for sentences.
+	IsWordLike bool `js:"isWordLike"`
+}
+
+// segmenter returns the first segment of text and the rest, as the uniseg First*InString
+// functions do.
+type segmenter func(text string, state int) (segment, rest string, newState int)
+
+// segments splits text with next and records the offsets of each segment.
+func segments(text string, next segmenter, words bool) []Segment {
+	out := []Segment{}
+	byteOffset, utf16Offset := 0, 0
+	state := -1
+	for len(text) > 0 {
+		var segment string
+		segment, text, state = next(text, state)
+		s := Segment{
+			Segment:     segment,
+			ByteOffset:  byteOffset,
+			ByteLength:  len(segment),
+			UTF16Offset: utf16Offset,
+			UTF16Length: utf16Length(segment),
+		}
+		if words {
+			s.IsWordLike = isWordLike(segment)
+		}
+		out = append(out, s)
+		byteOffset += s.ByteLength
+		utf16Offset += s.UTF16Length
+	}
+	return out
+}
+
+// utf16Length returns the number of UTF-16 code units needed to encode text.
+func utf16Length(text string) int {
+	n := 0
+	for _, r := range text {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// isWordLike reports whether a word segment holds a letter or a number.
+func isWordLike(word string) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// SegmentWords splits text at the word boundaries of UAX #29. Every character belongs
+// to a segment, so spaces and punctuation come back as segments that are not word-like.
+// Boundaries are found without a dictionary: CJK ideographs and Thai, Lao, Khmer and
+// Myanmar text, which do not separate words with spaces, come back one grapheme per segment.
+func (TextEncoding) SegmentWords(text string) ([]Segment, error) {
+	if err := validateText(text); err != nil {
+		return nil, err
+	}
+	return segments(text, uniseg.FirstWordInString, true), nil
+}
+
+// SegmentSentences splits text at the sentence boundaries of UAX #29. Each sentence
+// keeps its trailing spaces and line breaks.
+func (TextEncoding) SegmentSentences(text string) ([]Segment, error) {
+	if err := validateText(text); err != nil {
+		return nil, err
+	}
+	return segments(text, uniseg.FirstSentenceInString, false), nil
+}
@@ -0,0 +1,181 @@
+package text_encoding
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSegmentWords(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		words    []string
+		wordLike []string
+	}{
+		{
+			name:     "punctuation",
+			input:    "Hello, world!",
+			words:    []string{"Hello", ",", " ", "world", "!"},
+			wordLike: []string{"Hello", "world"},
+		},
+		{
+			name:     "contractions and numbers",
+			input:    "It's 3.14, can't 1,000",
+			words:    []string{"It's", " ", "3.14", ",", " ", "can't", " ", "1,000"},
+			wordLike: []string{"It's", "3.14", "can't", "1,000"},
+		},
+		{
+			name:     "ideographs",
+			input:    "你好 world",
+			words:    []string{"你", "好", " ", "world"},
+			wordLike: []string{"你", "好", "world"},
+		},
+		{
+			name:     "thai graphemes",
+			input:    "สวัสดี",
+			words:    []string{"ส", "วั", "ส", "ดี"},
+			wordLike: []string{"ส", "วั", "ส", "ดี"},
+		},
+		{
+			name:     "emoji",
+			input:    "👍🏽 ok 🇩🇪",
+			words:    []string{"👍🏽", " ", "ok", " ", "🇩🇪"},
+			wordLike: []string{"ok"},
+		},
+		{
+			name:     "combining mark",
+			input:    "cafe\u0301 au lait",
+			words:    []string{"cafe\u0301", " ", "au", " ", "lait"},
+			wordLike: []string{"cafe\u0301", "au", "lait"},
+		},
+		{name: "empty", input: "", words: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := te.SegmentWords(tt.input)
+			if err != nil {
+				t.Fatalf("SegmentWords() unexpected error: %v", err)
+			}
+			words := []string{}
+			var wordLike []string
+			for _, s := range segments {
+				words = append(words, s.Segment)
+				if s.IsWordLike {
+					wordLike = append(wordLike, s.Segment)
+				}
+			}
+			if !reflect.DeepEqual(words, tt.words) {
+				t.Errorf("SegmentWords(%q) = %q, want %q", tt.input, words, tt.words)
+			}
+			if !reflect.DeepEqual(wordLike, tt.wordLike) {
+				t.Errorf("SegmentWords(%q) word-like = %q, want %q", tt.input, wordLike, tt.wordLike)
+			}
+		})
+	}
+
+	if _, err := te.SegmentWords("\xff"); err == nil || !strings.Contains(err.Error(), ErrInvalidUTF8) {
+		t.Errorf("SegmentWords() error = %v, want %q", err, ErrInvalidUTF8)
+	}
+}
+
+func TestSegmentOffsets(t *testing.T) {
+	te := &TextEncoding{}
+
+	segments, err := te.SegmentWords("é 😀x")
+	if err != nil {
+		t.Fatalf("SegmentWords() unexpected error: %v", err)
+	}
+	expected := []Segment{
+		{Segment: "é", ByteOffset: 0, ByteLength: 2, UTF16Offset: 0, UTF16Length: 1, IsWordLike: true},
+		{Segment: " ", ByteOffset: 2, ByteLength: 1, UTF16Offset: 1, UTF16Length: 1},
+		{Segment: "😀", ByteOffset: 3, ByteLength: 4, UTF16Offset: 2, UTF16Length: 2},
+		{Segment: "x", ByteOffset: 7, ByteLength: 1, UTF16Offset: 4, UTF16Length: 1, IsWordLike: true},
+	}
+	if !reflect.DeepEqual(segments, expected) {
+		t.Errorf("SegmentWords() = %+v, want %+v", segments, expected)
+	}
+}
+
+func TestSegmentSentences(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name      string
+		input     string
+		sentences []string
+	}{
+		{
+			name:      "terminators",
+			input:     "Hello world. How are you? Fine!",
+			sentences: []string{"Hello world. ", "How are you? ", "Fine!"},
+		},
+		{
+			name:      "decimal and quotes",
+			input:     "It costs 3.50 today. He said \"Hi!\" Then left.",
+			sentences: []string{"It costs 3.50 today. ", "He said \"Hi!\" ", "Then left."},
+		},
+		{
+			name:      "lower case after period",
+			input:     "See e.g. the docs. Done.",
+			sentences: []string{"See e.g. the docs. ", "Done."},
+		},
+		{
+			name:      "paragraphs",
+			input:     "First line\nSecond line",
+			sentences: []string{"First line\n", "Second line"},
+		},
+		{
+			name:      "ideographic full stop",
+			input:     "你好。再见。",
+			sentences: []string{"你好。", "再见。"},
+		},
+		{name: "empty", input: "", sentences: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := te.SegmentSentences(tt.input)
+			if err != nil {
+				t.Fatalf("SegmentSentences() unexpected error: %v", err)
+			}
+			sentences := []string{}
+			offset := 0
+			for _, s := range segments {
+				sentences = append(sentences, s.Segment)
+				if s.ByteOffset != offset || s.IsWordLike {
+					t.Errorf("SegmentSentences(%q) segment %q = %+v, want byte offset %d", tt.input, s.Segment, s, offset)
+				}
+				offset += s.ByteLength
+			}
+			if !reflect.DeepEqual(sentences, tt.sentences) {
+				t.Errorf("SegmentSentences(%q) = %q, want %q", tt.input, sentences, tt.sentences)
+			}
+		})
+	}
+}
+
+func TestSegmentJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const text = 'Olá 👋 mundo. 你好!';
+		const words = encoding.segmentWords(text);
+		const sliced = words.every((w) => text.slice(w.utf16Offset, w.utf16Offset + w.utf16Length) === w.segment);
+		const bytes = encoding.encodeUTF8(text);
+		const last = words[words.length - 2];
+		return [
+			words.filter((w) => w.isWordLike).map((w) => w.segment).join(','),
+			sliced,
+			encoding.decodeUTF8(bytes.slice(last.byteOffset, last.byteOffset + last.byteLength)),
+			encoding.segmentSentences(text).map((s) => s.segment).join('|'),
+		].join(' / ');
+	})()`).String()
+	expected := "Olá,mundo,你,好 / true / 好 / Olá 👋 mundo. |你好!"
+	if result != expected {
+		t.Errorf("segments = %q, want %q", result, expected)
+	}
+}
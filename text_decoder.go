@@ -0,0 +1,258 @@
+package text_encoding
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+	"golang.org/x/text/transform"
+)
+
+// ErrUnsupportedEncoding is returned when an encoding label is not recognized.
+const ErrUnsupportedEncoding = "unsupported encoding"
+
+// TextDecoder implements the WHATWG TextDecoder interface.
+// A decoder keeps incomplete multi-byte sequences between streaming calls to Decode.
+type TextDecoder struct {
+	Encoding  string `js:"encoding"`
+	Fatal     bool   `js:"fatal"`
+	IgnoreBOM bool   `js:"ignoreBOM"`
+
+	rt       *sobek.Runtime
+	charset  *charset
+	legacy   transform.Transformer
+	utf16    byteOrder
+	stripBOM bool
+	pending  []byte
+	bomSeen  bool
+}
+
+// TextDecoderOptions are the options accepted by the TextDecoder constructor.
+type TextDecoderOptions struct {
+	Fatal     bool `js:"fatal"`
+	IgnoreBOM bool `js:"ignoreBOM"`
+}
+
+// TextDecodeOptions are the options accepted by TextDecoder.decode.
+type TextDecodeOptions struct {
+	Stream bool `js:"stream"`
+}
+
+// XTextDecoder is the JavaScript constructor for TextDecoder.
+// It throws a RangeError if the label does not name a supported encoding, and a TypeError
+// if it is called as a method without new.
+func (TextEncoding) XTextDecoder(call sobek.ConstructorCall, rt *sobek.Runtime) *sobek.Object {
+	requireNew(call, rt, "TextDecoder")
+	label := "utf-8"
+	if arg := call.Argument(0); !sobek.IsUndefined(arg) {
+		label = arg.String()
+	}
+	var opts TextDecoderOptions
+	if arg := call.Argument(1); !common.IsNullish(arg) {
+		if err := rt.ExportTo(arg, &opts); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+
+	decoder, err := NewTextDecoder(label, opts)
+	if err != nil {
+		panic(newRangeError(rt, err.Error()))
+	}
+	decoder.rt = rt
+	return rt.ToValue(decoder).ToObject(rt)
+}
+
+// newRangeError creates a JavaScript RangeError, which sobek has no shortcut for.
+func newRangeError(rt *sobek.Runtime, message string) *sobek.Object {
+	rangeError, err := rt.New(rt.Get("RangeError"), rt.ToValue(message))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rangeError
+}
+
+// NewTextDecoder creates a TextDecoder for the given encoding label.
+func NewTextDecoder(label string, opts TextDecoderOptions) (*TextDecoder, error) {
+	cs, err := lookupCharset(label)
+	if err != nil {
+		return nil, err
+	}
+	decoder := &TextDecoder{
+		Encoding:  cs.name,
+		Fatal:     opts.Fatal,
+		IgnoreBOM: opts.IgnoreBOM,
+		// Only the Unicode encodings have a byte order mark to strip.
+		stripBOM: !opts.IgnoreBOM && (cs.enc == nil || strings.HasPrefix(cs.name, "utf-16")),
+	}
+	if order := cs.utf16Order(); order != nil {
+		decoder.utf16 = order
+	} else if cs.enc != nil {
+		decoder.charset = cs
+		decoder.legacy = cs.enc.NewDecoder()
+	}
+	return decoder, nil
+}
+
+// Decode decodes an ArrayBuffer, TypedArray or DataView to a string.
+// With the stream option set, a trailing incomplete sequence is kept for the next call.
+func (d *TextDecoder) Decode(input sobek.Value, opts TextDecodeOptions) (string, error) {
+	data, err := bytesFromValue(d.rt, input)
+	if err != nil {
+		return "", err
+	}
+	return d.decodeBytes(data, opts.Stream)
+}
+
+// decodeBytes is the runtime-independent part of Decode.
+func (d *TextDecoder) decodeBytes(data []byte, stream bool) (string, error) {
+	if err := validateInputSize(len(d.pending) + len(data)); err != nil {
+		return "", err
+	}
+	if len(d.pending) > 0 {
+		data = append(d.pending, data...)
+		d.pending = nil
+	}
+
+	var text string
+	var rest []byte
+	var err error
+	switch {
+	case d.utf16 != nil:
+		text, rest, err = decodeUTF16Chunk(data, d.utf16, !d.Fatal, !stream, 0)
+	case d.legacy != nil:
+		text, rest, err = d.decodeLegacy(data, !stream)
+	default:
+		text, rest, _, err = decodeUTF8Chunk(data, d.Fatal, !stream)
+	}
+	if err != nil {
+		d.reset()
+		return "", err
+	}
+	if len(rest) > 0 {
+		d.pending = append([]byte(nil), rest...)
+	}
+
+	if !d.bomSeen && text != "" {
+		d.bomSeen = true
+		if d.stripBOM {
+			text = strings.TrimPrefix(text, "\uFEFF")
+		}
+	}
+	if !stream {
+		d.reset()
+	}
+	return text, nil
+}
+
+// decodeLegacy decodes data with a legacy character set decoder.
+// Unless flush is set, a trailing incomplete sequence is returned unconsumed.
+func (d *TextDecoder) decodeLegacy(data []byte, flush bool) (string, []byte, error) {
+	src := data
+	buf := make([]byte, 4*len(data)+16)
+	var out []byte
+	for {
+		nDst, nSrc, err := d.legacy.Transform(buf, data, flush)
+		out = append(out, buf[:nDst]...)
+		data = data[nSrc:]
+		if errors.Is(err, transform.ErrShortDst) {
+			if nDst == 0 {
+				buf = make([]byte, 2*len(buf))
+			}
+			continue
+		}
+		if err != nil && (flush || !errors.Is(err, transform.ErrShortSrc)) {
+			return "", nil, fmt.Errorf("%s %s: %w", ErrInvalidCharsetBytes, d.Encoding, err)
+		}
+		break
+	}
+
+	text := string(out)
+	if d.Fatal && d.charset.malformed(src[:len(src)-len(data)], text) {
+		return "", nil, fmt.Errorf("%s %s", ErrInvalidCharsetBytes, d.Encoding)
+	}
+	return text, data, nil
+}
+
+// reset returns the decoder to its initial state at the end of a stream or after an error.
+func (d *TextDecoder) reset() {
+	d.pending = nil
+	d.bomSeen = false
+	if d.legacy != nil {
+		d.legacy.Reset()
+	}
+}
+
+// decodeUTF8Chunk decodes data as UTF-8. Each maximal subpart of an invalid
+// sequence is replaced with U+FFFD, or reported as an error when fatal is set.
+// Unless flush is set, a trailing incomplete sequence is returned unconsumed.
+// The number of replacements made is returned alongside the text.
+func decodeUTF8Chunk(data []byte, fatal, flush bool) (string, []byte, int, error) {
+	if utf8.Valid(data) {
+		return string(data), nil, 0, nil
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(data))
+	replaced := 0
+	for i := 0; i < len(data); {
+		n, valid, truncated := scanUTF8(data[i:])
+		switch {
+		case valid:
+			sb.Write(data[i : i+n])
+		case truncated && !flush:
+			return sb.String(), data[i:], replaced, nil
+		case fatal:
+			return "", nil, 0, errors.New(ErrInvalidUTF8)
+		default:
+			sb.WriteRune(utf8.RuneError)
+			replaced++
+		}
+		i += n
+	}
+	return sb.String(), nil, replaced, nil
+}
+
+// scanUTF8 inspects the sequence starting at data[0], which must not be empty.
+// For a valid sequence it returns its length. For an invalid one it returns the
+// length of its maximal subpart and whether data ended before the sequence did.
+func scanUTF8(data []byte) (n int, valid, truncated bool) {
+	lead := data[0]
+	var need int
+	lower, upper := byte(0x80), byte(0xBF)
+	switch {
+	case lead < 0x80:
+		return 1, true, false
+	case lead >= 0xC2 && lead <= 0xDF:
+		need = 1
+	case lead >= 0xE0 && lead <= 0xEF:
+		need = 2
+		if lead == 0xE0 {
+			lower = 0xA0
+		} else if lead == 0xED {
+			upper = 0x9F
+		}
+	case lead >= 0xF0 && lead <= 0xF4:
+		need = 3
+		if lead == 0xF0 {
+			lower = 0x90
+		} else if lead == 0xF4 {
+			upper = 0x8F
+		}
+	default:
+		return 1, false, false
+	}
+
+	for i := 1; i <= need; i++ {
+		if i >= len(data) {
+			return i, false, true
+		}
+		if data[i] < lower || data[i] > upper {
+			return i, false, false
+		}
+		lower, upper = 0x80, 0xBF
+	}
+	return need + 1, true, false
+}
@@ -0,0 +1,355 @@
+package text_encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewTextDecoder(t *testing.T) {
+	tests := []struct {
+		name        string
+		label       string
+		expectError bool
+	}{
+		{name: "canonical label", label: "utf-8"},
+		{name: "alias", label: "utf8"},
+		{name: "mixed case and whitespace", label: " UTF-8\n"},
+		{name: "legacy alias", label: "unicode-1-1-utf-8"},
+		{name: "unknown label", label: "klingon", expectError: true},
+		{name: "empty label", label: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := NewTextDecoder(tt.label, TextDecoderOptions{})
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("NewTextDecoder(%q) expected error but got none", tt.label)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewTextDecoder(%q) unexpected error: %v", tt.label, err)
+			}
+			if decoder.Encoding != "utf-8" {
+				t.Errorf("Encoding = %q, want %q", decoder.Encoding, "utf-8")
+			}
+		})
+	}
+}
+
+func TestTextDecoderDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        TextDecoderOptions
+		input       []byte
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "empty input",
+			input:    []byte{},
+			expected: "",
+		},
+		{
+			name:     "valid unicode",
+			input:    []byte("Hello 🌍 你好"),
+			expected: "Hello 🌍 你好",
+		},
+		{
+			name:     "strips BOM",
+			input:    []byte{0xEF, 0xBB, 0xBF, 'h', 'i'},
+			expected: "hi",
+		},
+		{
+			name:     "keeps BOM when ignoreBOM is set",
+			opts:     TextDecoderOptions{IgnoreBOM: true},
+			input:    []byte{0xEF, 0xBB, 0xBF, 'h', 'i'},
+			expected: "\uFEFFhi",
+		},
+		{
+			name:     "strips only the first BOM",
+			input:    []byte{0xEF, 0xBB, 0xBF, 0xEF, 0xBB, 0xBF},
+			expected: "\uFEFF",
+		},
+		{
+			name:     "replaces invalid bytes",
+			input:    []byte{'a', 0xFF, 'b'},
+			expected: "a\uFFFDb",
+		},
+		{
+			name:     "replaces maximal subpart once",
+			input:    []byte{0xF0, 0x9F, 0x8C, 'a'},
+			expected: "\uFFFDa",
+		},
+		{
+			name:     "replaces truncated sequence at end",
+			input:    []byte{'a', 0xE4, 0xBD},
+			expected: "a\uFFFD",
+		},
+		{
+			name:     "replaces surrogate bytes individually",
+			input:    []byte{0xED, 0xA0, 0x80},
+			expected: "\uFFFD\uFFFD\uFFFD",
+		},
+		{
+			name:        "fatal rejects invalid bytes",
+			opts:        TextDecoderOptions{Fatal: true},
+			input:       []byte{'a', 0xFF},
+			expectError: true,
+		},
+		{
+			name:        "fatal rejects truncated sequence",
+			opts:        TextDecoderOptions{Fatal: true},
+			input:       []byte{0xF0, 0x9F},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := NewTextDecoder("utf-8", tt.opts)
+			if err != nil {
+				t.Fatalf("NewTextDecoder() error: %v", err)
+			}
+			result, err := decoder.decodeBytes(tt.input, false)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("decode() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("decode() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTextDecoderStreaming(t *testing.T) {
+	input := []byte("\uFEFFcafé 🌍 你好")
+
+	// Feed the input one byte at a time so every multi-byte sequence is split.
+	decoder, err := NewTextDecoder("utf-8", TextDecoderOptions{Fatal: true})
+	if err != nil {
+		t.Fatalf("NewTextDecoder() error: %v", err)
+	}
+	var result string
+	for i := range input {
+		chunk, err := decoder.decodeBytes(input[i:i+1], true)
+		if err != nil {
+			t.Fatalf("decode() chunk %d error: %v", i, err)
+		}
+		result += chunk
+	}
+	tail, err := decoder.decodeBytes(nil, false)
+	if err != nil {
+		t.Fatalf("decode() flush error: %v", err)
+	}
+	result += tail
+	if result != "café 🌍 你好" {
+		t.Errorf("streamed decode = %q, want %q", result, "café 🌍 你好")
+	}
+
+	// An incomplete sequence at the end of the stream is replaced on flush.
+	decoder, _ = NewTextDecoder("utf-8", TextDecoderOptions{})
+	first, _ := decoder.decodeBytes([]byte{'a', 0xE4}, true)
+	second, _ := decoder.decodeBytes(nil, false)
+	if first != "a" || second != "\uFFFD" {
+		t.Errorf("flush of incomplete sequence = %q, %q, want %q, %q", first, second, "a", "\uFFFD")
+	}
+
+	// The decoder is reusable after a flush and strips a new BOM.
+	third, _ := decoder.decodeBytes([]byte{0xEF, 0xBB, 0xBF, 'b'}, false)
+	if third != "b" {
+		t.Errorf("decode() after flush = %q, want %q", third, "b")
+	}
+}
+
+func TestTextDecoderLegacyCharset(t *testing.T) {
+	decoder, err := NewTextDecoder("latin1", TextDecoderOptions{})
+	if err != nil {
+		t.Fatalf("NewTextDecoder() error: %v", err)
+	}
+	if decoder.Encoding != "windows-1252" {
+		t.Errorf("Encoding = %q, want %q", decoder.Encoding, "windows-1252")
+	}
+	result, err := decoder.decodeBytes([]byte{0x80, ' ', 'c', 'a', 'f', 0xE9}, false)
+	if err != nil {
+		t.Fatalf("decode() unexpected error: %v", err)
+	}
+	if result != "€ café" {
+		t.Errorf("decode() = %q, want %q", result, "€ café")
+	}
+
+	// A BOM is only stripped for UTF-8.
+	result, _ = decoder.decodeBytes([]byte{0xEF, 0xBB, 0xBF}, false)
+	if result != "ï»¿" {
+		t.Errorf("decode() = %q, want %q", result, "ï»¿")
+	}
+
+	decoder, _ = NewTextDecoder("windows-1253", TextDecoderOptions{Fatal: true})
+	if _, err := decoder.decodeBytes([]byte{'a', 0xAA}, false); err == nil {
+		t.Error("decode() expected error for undefined byte in fatal mode")
+	}
+	decoder, _ = NewTextDecoder("gb18030", TextDecoderOptions{Fatal: true})
+	if _, err := decoder.decodeBytes([]byte{0x84, 0x31, 0xA4, 0x37, 'a', 0x81}, false); err == nil {
+		t.Error("decode() expected error for truncated GB18030 in fatal mode")
+	}
+}
+
+func TestTextDecoderMultiByteStreaming(t *testing.T) {
+	tests := []struct {
+		label string
+		input []byte
+		text  string
+	}{
+		{label: "shift_jis", input: []byte{0x93, 0xFA, 0x96, 0x7B, 0x8C, 0xEA}, text: "日本語"},
+		{
+			label: "iso-2022-jp",
+			input: []byte{'a', 0x1B, 0x24, 0x42, 0x46, 0x7C, 0x4B, 0x5C, 0x1B, 0x28, 0x42, 'b'},
+			text:  "a日本b",
+		},
+		{label: "gb18030", input: []byte{0xD6, 0xD0, 0x94, 0x39, 0xFC, 0x36, 0x84, 0x31, 0xA4, 0x37}, text: "中😀\uFFFD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			decoder, err := NewTextDecoder(tt.label, TextDecoderOptions{Fatal: true})
+			if err != nil {
+				t.Fatalf("NewTextDecoder() error: %v", err)
+			}
+			var result string
+			for i := range tt.input {
+				chunk, err := decoder.decodeBytes(tt.input[i:i+1], true)
+				if err != nil {
+					t.Fatalf("decode() chunk %d error: %v", i, err)
+				}
+				result += chunk
+			}
+			tail, err := decoder.decodeBytes(nil, false)
+			if err != nil {
+				t.Fatalf("decode() flush error: %v", err)
+			}
+			result += tail
+			if result != tt.text {
+				t.Errorf("streamed decode = %q, want %q", result, tt.text)
+			}
+		})
+	}
+}
+
+func TestTextDecoderJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	tests := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{
+			name:     "default properties",
+			script:   `(() => { const d = new encoding.TextDecoder(); return [d.encoding, d.fatal, d.ignoreBOM].join('|'); })()`,
+			expected: "utf-8|false|false",
+		},
+		{
+			name:     "constructor options",
+			script:   `(() => { const d = new encoding.TextDecoder('utf8', { fatal: true, ignoreBOM: true }); return [d.encoding, d.fatal, d.ignoreBOM].join('|'); })()`,
+			expected: "utf-8|true|true",
+		},
+		{
+			name:     "decode Uint8Array",
+			script:   `new encoding.TextDecoder().decode(new Uint8Array([104, 105]))`,
+			expected: "hi",
+		},
+		{
+			name:     "decode ArrayBuffer",
+			script:   `new encoding.TextDecoder().decode(new Uint8Array([240, 159, 140, 141]).buffer)`,
+			expected: "🌍",
+		},
+		{
+			name:     "decode subarray",
+			script:   `new encoding.TextDecoder().decode(new Uint8Array([0, 104, 105, 0]).subarray(1, 3))`,
+			expected: "hi",
+		},
+		{
+			name:     "decode DataView",
+			script:   `new encoding.TextDecoder().decode(new DataView(new Uint8Array([0, 104, 105]).buffer, 1))`,
+			expected: "hi",
+		},
+		{
+			name:     "fatal utf-16 decodes U+FFFD",
+			script:   `new encoding.TextDecoder('utf-16le', { fatal: true }).decode(new Uint8Array([0xFD, 0xFF, 0x41, 0x00]))`,
+			expected: "\uFFFDA",
+		},
+		{
+			name:     "decode without input",
+			script:   `new encoding.TextDecoder().decode()`,
+			expected: "",
+		},
+		{
+			name: "streaming",
+			script: `(() => {
+				const d = new encoding.TextDecoder();
+				const bytes = new encoding.TextEncoder().encode('a🌍b');
+				let out = '';
+				for (let i = 0; i < bytes.length; i++) {
+					out += d.decode(bytes.subarray(i, i + 1), { stream: true });
+				}
+				return out + d.decode();
+			})()`,
+			expected: "a🌍b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runScript(t, rt, tt.script).String()
+			if result != tt.expected {
+				t.Errorf("%s = %q, want %q", tt.name, result, tt.expected)
+			}
+		})
+	}
+
+	throwing := []string{
+		`new encoding.TextDecoder('klingon')`,
+		`new encoding.TextDecoder('utf-8', { fatal: true }).decode(new Uint8Array([255]))`,
+		`new encoding.TextDecoder().decode({})`,
+	}
+	for _, script := range throwing {
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected to throw", script)
+		}
+	}
+
+	for _, script := range []string{
+		`new encoding.TextDecoder('klingon')`,
+		`new encoding.TextDecoder('replacement')`,
+	} {
+		v, err := rt.RunString(`try { ` + script + `; 'no error' } catch (e) { e instanceof RangeError ? e.message : String(e) }`)
+		if err != nil || !strings.Contains(v.String(), ErrUnsupportedEncoding) {
+			t.Errorf("%s threw %v (%v), want a RangeError", script, v, err)
+		}
+	}
+
+	// Objects shaped like views are not views.
+	for _, script := range []string{
+		`new encoding.TextDecoder().decode({ buffer: new ArrayBuffer(4) })`,
+		`encoding.encodeBase64({ buffer: new ArrayBuffer(4) })`,
+		`encoding.encodeHex({ buffer: new ArrayBuffer(4), byteOffset: 0 })`,
+		`encoding.encodeHex({ buffer: new ArrayBuffer(4), byteOffset: 0, byteLength: 4 })`,
+	} {
+		if _, err := rt.RunString(script); err == nil || !strings.Contains(err.Error(), ErrNotBufferSource) {
+			t.Errorf("%s error = %v, want %q", script, err, ErrNotBufferSource)
+		}
+	}
+
+	for _, script := range []string{`encoding.TextDecoder('utf-8')`, `encoding.TextEncoder()`} {
+		if _, err := rt.RunString(script); err == nil || !strings.Contains(err.Error(), "TypeError") {
+			t.Errorf("%s error = %v, want a TypeError", script, err)
+		}
+	}
+}
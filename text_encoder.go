@@ -0,0 +1,82 @@
+package text_encoding
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/grafana/sobek"
+)
+
+// TextEncoder implements the WHATWG TextEncoder interface.
+// It always encodes to UTF-8, so its encoding property is fixed to "utf-8".
+type TextEncoder struct {
+	Encoding string `js:"encoding"`
+
+	rt *sobek.Runtime
+}
+
+// EncodeIntoResult reports the progress of TextEncoder.encodeInto.
+// Read is counted in UTF-16 code units of the source, Written in bytes.
+type EncodeIntoResult struct {
+	Read    int `js:"read"`
+	Written int `js:"written"`
+}
+
+// XTextEncoder is the JavaScript constructor for TextEncoder.
+// It throws a TypeError if it is called as a method without new.
+func (TextEncoding) XTextEncoder(call sobek.ConstructorCall, rt *sobek.Runtime) *sobek.Object {
+	requireNew(call, rt, "TextEncoder")
+	return rt.ToValue(&TextEncoder{Encoding: "utf-8", rt: rt}).ToObject(rt)
+}
+
+// requireNew throws a TypeError, as WHATWG constructors do, when a constructor is called
+// as a method, such as encoding.TextDecoder(). Sobek gives constructors no new.target, so
+// this is told from new by the receiver: an instance made by new inherits from the
+// constructor's own prototype, never from Object.prototype or null. A bare call with an
+// undefined receiver is constructed by sobek as if by new and cannot be told apart.
+func requireNew(call sobek.ConstructorCall, rt *sobek.Runtime, name string) {
+	proto := call.This.Prototype()
+	if proto == nil || proto.SameAs(rt.NewObject().Prototype()) {
+		panic(rt.NewTypeError("Failed to construct '%s': Please use the 'new' operator", name))
+	}
+}
+
+// Encode converts a string to UTF-8 and returns the bytes as a Uint8Array.
+// Lone surrogates in the input are encoded as U+FFFD.
+func (e *TextEncoder) Encode(input string) (*sobek.Object, error) {
+	if err := validateInputSize(len(input)); err != nil {
+		return nil, err
+	}
+	return newUint8Array(e.rt, []byte(input))
+}
+
+// EncodeInto writes the UTF-8 encoding of source into the destination Uint8Array.
+// Encoding stops before the first character that does not fit completely.
+func (e *TextEncoder) EncodeInto(source string, destination sobek.Value) (*EncodeIntoResult, error) {
+	if err := validateInputSize(len(source)); err != nil {
+		return nil, err
+	}
+	if _, ok := destination.Export().([]byte); !ok {
+		return nil, errors.New(ErrNotBufferSource)
+	}
+	dst, err := bytesFromValue(e.rt, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EncodeIntoResult{}
+	for _, r := range source {
+		size := utf8.RuneLen(r)
+		if result.Written+size > len(dst) {
+			break
+		}
+		utf8.EncodeRune(dst[result.Written:], r)
+		result.Written += size
+		if r >= 0x10000 {
+			result.Read += 2
+		} else {
+			result.Read++
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package text_encoding
+
+import (
+	"testing"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// newTestRuntime returns a runtime with the module bound to the global "encoding",
+// mirroring how k6 exposes it to scripts.
+func newTestRuntime(t *testing.T) *sobek.Runtime {
+	t.Helper()
+	rt := sobek.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+	if err := rt.Set("encoding", newTextEncoding(rt)); err != nil {
+		t.Fatalf("failed to bind module: %v", err)
+	}
+	return rt
+}
+
+// runScript evaluates a script and fails the test if it throws.
+func runScript(t *testing.T, rt *sobek.Runtime, script string) sobek.Value {
+	t.Helper()
+	v, err := rt.RunString(script)
+	if err != nil {
+		t.Fatalf("script failed: %v", err)
+	}
+	return v
+}
+
+func TestTextEncoderEncode(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	tests := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{
+			name:     "encoding property",
+			script:   `new encoding.TextEncoder().encoding`,
+			expected: "utf-8",
+		},
+		{
+			name:     "returns Uint8Array",
+			script:   `new encoding.TextEncoder().encode('hi') instanceof Uint8Array`,
+			expected: "true",
+		},
+		{
+			name:     "unicode bytes",
+			script:   `Array.from(new encoding.TextEncoder().encode('Hello 🌍')).join(',')`,
+			expected: "72,101,108,108,111,32,240,159,140,141",
+		},
+		{
+			name:     "no argument",
+			script:   `new encoding.TextEncoder().encode().length`,
+			expected: "0",
+		},
+		{
+			name:     "lone surrogate",
+			script:   `Array.from(new encoding.TextEncoder().encode('\uD800')).join(',')`,
+			expected: "239,191,189",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runScript(t, rt, tt.script).String()
+			if result != tt.expected {
+				t.Errorf("%s = %q, want %q", tt.script, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTextEncoderEncodeInto(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	tests := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{
+			name: "fits completely",
+			script: `(() => {
+				const dest = new Uint8Array(8);
+				const r = new encoding.TextEncoder().encodeInto('abc', dest);
+				return [r.read, r.written, Array.from(dest.subarray(0, 3)).join(' ')].join('|');
+			})()`,
+			expected: "3|3|97 98 99",
+		},
+		{
+			name: "stops before partial character",
+			script: `(() => {
+				const dest = new Uint8Array(5);
+				const r = new encoding.TextEncoder().encodeInto('a🌍b', dest);
+				return [r.read, r.written].join('|');
+			})()`,
+			expected: "3|5",
+		},
+		{
+			name: "writes at view offset",
+			script: `(() => {
+				const buf = new Uint8Array(6);
+				const r = new encoding.TextEncoder().encodeInto('é', buf.subarray(2, 4));
+				return [r.read, r.written, Array.from(buf).join(' ')].join('|');
+			})()`,
+			expected: "1|2|0 0 195 169 0 0",
+		},
+		{
+			name: "destination too small",
+			script: `(() => {
+				const r = new encoding.TextEncoder().encodeInto('你', new Uint8Array(2));
+				return [r.read, r.written].join('|');
+			})()`,
+			expected: "0|0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runScript(t, rt, tt.script).String()
+			if result != tt.expected {
+				t.Errorf("encodeInto() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := rt.RunString(`new encoding.TextEncoder().encodeInto('abc', {})`); err == nil {
+		t.Error("encodeInto() expected error for non-Uint8Array destination")
+	}
+}
@@ -1,33 +1,74 @@
 package text_encoding
 
 import (
-	"encoding/base64"
 	"errors"
 	"fmt"
+	"unicode/utf16"
 	"unicode/utf8"
 
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
 )
 
 // Error messages
 const (
-	ErrInvalidUTF8       = "invalid UTF-8 bytes"
-	ErrInvalidBase64     = "failed to decode base64"
-	ErrInvalidUTF8Base64 = "decoded data is not valid UTF-8"
-	ErrEmptyInput        = "empty input"
-	ErrNilInput          = "nil input"
+	ErrInvalidUTF8   = "invalid UTF-8 bytes"
+	ErrInvalidBase64 = "failed to decode base64"
+	// ErrInvalidDecodedUTF8 is returned when the bytes decoded from base64, hex or another
+	// binary-to-text encoding are not valid UTF-8 text.
+	ErrInvalidDecodedUTF8 = "decoded data is not valid UTF-8"
+	ErrEmptyInput         = "empty input"
+	ErrNilInput           = "nil input"
 )
 
+// ErrInvalidUTF8Base64 is the former name of ErrInvalidDecodedUTF8.
+//
+// Deprecated: use ErrInvalidDecodedUTF8.
+const ErrInvalidUTF8Base64 = ErrInvalidDecodedUTF8
+
 // MaxInputSize is the maximum size of input strings to prevent memory issues
 const MaxInputSize = 100 * 1024 * 1024 // 100MB
 
 func init() {
-	modules.Register("k6/x/text-encoding", new(TextEncoding))
+	modules.Register("k6/x/text-encoding", new(RootModule))
+}
+
+// RootModule is the global module instance that creates a ModuleInstance for each VU.
+type RootModule struct{}
+
+// ModuleInstance is the module of one VU.
+type ModuleInstance struct {
+	exports *TextEncoding
+}
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// NewModuleInstance implements modules.Module. Each VU gets its own TextEncoding,
+// since its Buffer class is made of objects of the VU's runtime.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &ModuleInstance{exports: newTextEncoding(vu.Runtime())}
+}
+
+// Exports implements modules.Instance.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{Default: mi.exports}
 }
 
 // TextEncoding is the main module exposed to k6 JavaScript.
 // It provides functions for encoding and decoding text in various formats.
-type TextEncoding struct{}
+type TextEncoding struct {
+	// Buffer is a Node.js compatible Buffer class built on the module's codecs.
+	Buffer *sobek.Object `js:"Buffer"`
+}
+
+// newTextEncoding returns the module bound to a runtime.
+func newTextEncoding(rt *sobek.Runtime) *TextEncoding {
+	return &TextEncoding{Buffer: newBufferClass(rt).constructor}
+}
 
 // EncodeUTF8 converts a string to UTF-8 bytes.
 // It validates the input and returns an error if the input is invalid.
@@ -46,7 +87,8 @@ func (TextEncoding) EncodeUTF8(text string) ([]byte, error) {
 
 // EncodeUTF8ToBase64 converts a string to UTF-8 bytes and then to base64.
 // It validates the input and returns an error if the input is invalid.
-func (TextEncoding) EncodeUTF8ToBase64(text string) (string, error) {
+// The options select the alphabet, padding and MIME line wrapping.
+func (TextEncoding) EncodeUTF8ToBase64(text string, opts ...Base64Options) (string, error) {
 	if text == "" {
 		return "", nil
 	}
@@ -56,7 +98,7 @@ func (TextEncoding) EncodeUTF8ToBase64(text string) (string, error) {
 	if !utf8.ValidString(text) {
 		return "", errors.New(ErrInvalidUTF8)
 	}
-	return base64.StdEncoding.EncodeToString([]byte(text)), nil
+	return base64Options(opts).encode([]byte(text))
 }
 
 // DecodeUTF8 converts UTF-8 bytes back to string with validation.
@@ -76,23 +118,56 @@ func (TextEncoding) DecodeUTF8(data []byte) (string, error) {
 
 // DecodeUTF8FromBase64 decodes base64 string to UTF-8 text.
 // It validates both the base64 encoding and the resulting UTF-8.
-func (TextEncoding) DecodeUTF8FromBase64(encodedData string) (string, error) {
+// The options select the alphabet, padding, lenient whitespace handling and strict mode.
+func (TextEncoding) DecodeUTF8FromBase64(encodedData string, opts ...Base64Options) (string, error) {
 	if encodedData == "" {
 		return "", nil
 	}
 	if len(encodedData) > MaxInputSize {
 		return "", fmt.Errorf("input size exceeds maximum allowed size of %d bytes", MaxInputSize)
 	}
-	decoded, err := base64.StdEncoding.DecodeString(encodedData)
+	decoded, err := base64Options(opts).decode(encodedData)
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", ErrInvalidBase64, err)
+		return "", err
 	}
 	if !utf8.Valid(decoded) {
-		return "", errors.New(ErrInvalidUTF8Base64)
+		return "", errors.New(ErrInvalidDecodedUTF8)
 	}
 	return string(decoded), nil
 }
 
+// ReplacementResult is the outcome of a decode in replacement mode.
+type ReplacementResult struct {
+	Text         string `js:"text"`
+	Replacements int    `js:"replacements"`
+}
+
+// DecodeUTF8WithReplacement converts UTF-8 bytes to string without failing on invalid input.
+// Each maximal subpart of an invalid sequence is replaced with U+FFFD, as in the WHATWG
+// Encoding Standard, and the number of replacements is reported.
+func (TextEncoding) DecodeUTF8WithReplacement(data []byte) (*ReplacementResult, error) {
+	if err := validateInputSize(len(data)); err != nil {
+		return nil, err
+	}
+	text, _, replaced, _ := decodeUTF8Chunk(data, false, true)
+	return &ReplacementResult{Text: text, Replacements: replaced}, nil
+}
+
+// DecodeUTF8FromBase64WithReplacement decodes base64 string to UTF-8 text in replacement mode.
+// The base64 encoding must be valid; invalid UTF-8 in the decoded data is replaced with U+FFFD.
+// It accepts the same options as DecodeUTF8FromBase64.
+func (TextEncoding) DecodeUTF8FromBase64WithReplacement(encodedData string, opts ...Base64Options) (*ReplacementResult, error) {
+	if err := validateInputSize(len(encodedData)); err != nil {
+		return nil, err
+	}
+	decoded, err := base64Options(opts).decode(encodedData)
+	if err != nil {
+		return nil, err
+	}
+	text, _, replaced, _ := decodeUTF8Chunk(decoded, false, true)
+	return &ReplacementResult{Text: text, Replacements: replaced}, nil
+}
+
 // CountUTF8Bytes returns the number of bytes in UTF-8 encoding of the string.
 // It validates the input and returns an error if the input is invalid.
 func (TextEncoding) CountUTF8Bytes(text string) (int, error) {
@@ -147,9 +222,15 @@ func (TextEncoding) IsValidUTF8Bytes(data []byte) (bool, error) {
 	return utf8.Valid(data), nil
 }
 
+// Latin1Options configures StringToBytes.
+// Fallback is applied to characters above U+00FF: "error" (default), "replace", "html" or "skip".
+type Latin1Options struct {
+	Fallback string `js:"fallback"`
+}
+
 // BytesToString converts bytes to string without UTF-8 validation.
-// Each byte is treated as a single character (ISO-8859-1 encoding).
-// This is faster than UTF-8 decoding but only works for single-byte encodings.
+// Each byte is treated as a single character (ISO-8859-1 encoding), so bytes
+// 0x80-0xFF become U+0080-U+00FF and the string has one character per byte.
 func (TextEncoding) BytesToString(data []byte) (string, error) {
 	if len(data) == 0 {
 		return "", nil
@@ -157,7 +238,88 @@ func (TextEncoding) BytesToString(data []byte) (string, error) {
 	if len(data) > MaxInputSize {
 		return "", fmt.Errorf("input size exceeds maximum allowed size of %d bytes", MaxInputSize)
 	}
-	return string(data), nil
+	return latin1String(data), nil
+}
+
+// StringToBytes converts a string to ISO-8859-1 bytes, the reverse of BytesToString, and
+// returns them as an ArrayBuffer. Characters up to U+00FF become single bytes; others are
+// handled by the fallback option, so strings made by BytesToString round-trip exactly.
+func (TextEncoding) StringToBytes(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	text, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	var o Latin1Options
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	b, err := o.encode(text)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(b))
+}
+
+// encode converts text to ISO-8859-1 bytes, applying the fallback to characters above U+00FF.
+func (o Latin1Options) encode(text string) ([]byte, error) {
+	fallback, err := fallbackPolicy(o.Fallback)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateInputSize(len(text)); err != nil {
+		return nil, err
+	}
+
+	return latin1Bytes(text, func(out []byte, r rune, index int) ([]byte, error) {
+		switch fallback {
+		case FallbackError:
+			return nil, fmt.Errorf("%s: %q (U+%04X) at index %d is not representable in ISO-8859-1",
+				ErrUnmappableCharacter, r, r, index)
+		case FallbackReplace:
+			out = append(out, '?')
+		case FallbackHTML:
+			out = fmt.Appendf(out, "&#%d;", r)
+		}
+		return out, nil
+	})
+}
+
+// latin1Bytes converts text to ISO-8859-1 bytes. Each character above U+00FF is passed to
+// unmappable with its index in the JavaScript string, counted in UTF-16 code units, and
+// unmappable returns out with any replacement appended.
+func latin1Bytes(text string, unmappable func(out []byte, r rune, index int) ([]byte, error)) ([]byte, error) {
+	out := make([]byte, 0, len(text))
+	index := 0
+	for _, r := range text {
+		if r <= 0xFF {
+			out = append(out, byte(r))
+		} else {
+			var err error
+			if out, err = unmappable(out, r, index); err != nil {
+				return nil, err
+			}
+		}
+		index += utf16.RuneLen(r)
+	}
+	return out, nil
+}
+
+// latin1String decodes ISO-8859-1 bytes, copying ASCII input as it is.
+func latin1String(data []byte) string {
+	high := 0
+	for _, b := range data {
+		if b >= utf8.RuneSelf {
+			high++
+		}
+	}
+	if high == 0 {
+		return string(data)
+	}
+	out := make([]byte, 0, len(data)+high)
+	for _, b := range data {
+		out = utf8.AppendRune(out, rune(b))
+	}
+	return string(out)
 }
 
 // Helper function to validate input size
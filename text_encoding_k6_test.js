@@ -87,6 +87,81 @@ export default function () {
   // Test bytesToString function
   testBytesToString();
   
+  // Test TextEncoder and TextDecoder classes
+  testTextEncoderDecoder();
+  
+  // Test decoding with replacement
+  testDecodeWithReplacement();
+  
+  // Test diagnoseUTF8 function
+  testDiagnoseUTF8();
+  
+  // Test legacy single-byte character sets
+  testSingleByteCharsets();
+  
+  // Test CJK multi-byte character sets
+  testMultiByteCharsets();
+  
+  // Test UTF-16 and UTF-32 functions
+  testUTF16AndUTF32();
+  
+  // Test transcoding
+  testTranscode();
+  
+  // Test base64 options
+  testBase64Options();
+  
+  // Test binary base64
+  testBinaryBase64();
+  
+  // Test hex
+  testHex();
+  
+  // Test base32
+  testBase32();
+  
+  // Test Base58
+  testBase58();
+  
+  // Test Bech32
+  testBech32();
+  
+  // Test base85
+  testBase85();
+  
+  // Test Base45
+  testBase45();
+  
+  // Test arbitrary radix encoding
+  testRadix();
+  
+  // Test uuencode and xxencode
+  testUUEncode();
+  
+  // Test Latin-1 bytesToString and stringToBytes
+  testStringToBytes();
+  
+  // Test binary string conversion
+  testBinaryStrings();
+  
+  // Test Node.js compatible Buffer
+  testBuffer();
+  
+  // Test Unicode normalization
+  testNormalization();
+  
+  // Test grapheme clusters
+  testGraphemes();
+  
+  // Test word and sentence segmentation
+  testSegmentation();
+  
+  // Test line breaking and wrapping
+  testLineBreaking();
+  
+  // Test display width, padding and truncation
+  testDisplayWidth();
+  
   console.log('\n=== All Tests Completed Successfully! ===');
 }
 
@@ -656,4 +731,744 @@ export function testBytesToString() {
   }
   
   console.log('✓ bytesToString tests passed\n');
+}
+
+// Test TextEncoder and TextDecoder classes
+function testTextEncoderDecoder() {
+  console.log('Testing TextEncoder and TextDecoder...');
+  
+  const encoder = new encoding.TextEncoder();
+  assertEqual(encoder.encoding, 'utf-8', 'TextEncoder encoding should be utf-8');
+  
+  const bytes = encoder.encode('Hello 🌍');
+  assert(bytes instanceof Uint8Array, 'encode should return a Uint8Array');
+  assertEqual(bytes.length, 10, 'Unicode with emoji should be 10 bytes');
+  
+  // encodeInto stops before a character that does not fit
+  const dest = new Uint8Array(8);
+  const result = encoder.encodeInto('Hello 🌍', dest);
+  assertEqual(result.read, 6, 'encodeInto should read 6 code units');
+  assertEqual(result.written, 6, 'encodeInto should write 6 bytes');
+  
+  const decoder = new encoding.TextDecoder();
+  assertEqual(decoder.encoding, 'utf-8', 'TextDecoder encoding should be utf-8');
+  assertEqual(decoder.fatal, false, 'TextDecoder should not be fatal by default');
+  assertEqual(decoder.ignoreBOM, false, 'TextDecoder should not ignore BOM by default');
+  assertEqual(decoder.decode(bytes), 'Hello 🌍', 'decode should round-trip');
+  assertEqual(decoder.decode(bytes.buffer), 'Hello 🌍', 'decode should accept an ArrayBuffer');
+  assertEqual(decoder.decode(new Uint8Array([0xEF, 0xBB, 0xBF, 0x68, 0x69])), 'hi', 'decode should strip the BOM');
+  assertEqual(decoder.decode(new Uint8Array([0x61, 0xFF, 0x62])), 'a\uFFFDb', 'decode should replace invalid bytes');
+  
+  // Streaming keeps partial sequences between calls
+  let streamed = '';
+  for (let i = 0; i < bytes.length; i++) {
+    streamed += decoder.decode(bytes.subarray(i, i + 1), { stream: true });
+  }
+  streamed += decoder.decode();
+  assertEqual(streamed, 'Hello 🌍', 'streaming decode should join split sequences');
+  
+  const fatal = new encoding.TextDecoder('utf-8', { fatal: true });
+  let threw = false;
+  try {
+    fatal.decode(new Uint8Array([0xFF]));
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'fatal decoder should throw on invalid bytes');
+  
+  // Like the browser classes, both must be constructed with new
+  for (const call of [() => encoding.TextEncoder(), () => encoding.TextDecoder('utf-8')]) {
+    threw = false;
+    try {
+      call();
+    } catch (e) {
+      threw = e instanceof TypeError;
+    }
+    assert(threw, 'calling a constructor without new should throw a TypeError');
+  }
+  
+  console.log('✓ TextEncoder and TextDecoder tests passed\n');
+}
+
+// Test decoding with U+FFFD replacement
+function testDecodeWithReplacement() {
+  console.log('Testing decodeUTF8WithReplacement...');
+  
+  let result = encoding.decodeUTF8WithReplacement(new Uint8Array([0x68, 0x69]));
+  assertEqual(result.text, 'hi', 'Valid bytes should decode unchanged');
+  assertEqual(result.replacements, 0, 'Valid bytes should need no replacements');
+  
+  result = encoding.decodeUTF8WithReplacement(new Uint8Array([0x6F, 0x6B, 0xFF, 0xF0, 0x9F]));
+  assertEqual(result.text, 'ok\uFFFD\uFFFD', 'Invalid sequences should be replaced');
+  assertEqual(result.replacements, 2, 'Each invalid sequence should count once');
+  
+  result = encoding.decodeUTF8FromBase64WithReplacement('b2v/');
+  assertEqual(result.text, 'ok\uFFFD', 'Base64 payload should decode with replacement');
+  assertEqual(result.replacements, 1, 'Base64 payload should report one replacement');
+  
+  console.log('✓ decodeUTF8WithReplacement tests passed\n');
+}
+
+// Test diagnoseUTF8 function
+function testDiagnoseUTF8() {
+  console.log('Testing diagnoseUTF8...');
+  
+  let report = encoding.diagnoseUTF8(encoding.encodeUTF8('Hello 🌍'));
+  assert(report.valid, 'Valid UTF-8 should have no findings');
+  assertEqual(report.findings.length, 0, 'Valid UTF-8 should have no findings');
+  
+  report = encoding.diagnoseUTF8(new Uint8Array([0x61, 0xED, 0xA0, 0x80, 0xC0, 0xAF, 0xF0, 0x9F]));
+  assert(!report.valid, 'Invalid UTF-8 should be reported');
+  assertEqual(report.findings.length, 3, 'Should report three invalid sequences');
+  assertEqual(report.findings[0].offset, 1, 'Surrogate offset');
+  assertEqual(report.findings[0].bytes, 'ED A0 80', 'Surrogate bytes');
+  assertEqual(report.findings[0].kind, 'surrogate', 'Surrogate kind');
+  assertEqual(report.findings[1].kind, 'overlong', 'Overlong kind');
+  assertEqual(report.findings[2].kind, 'truncated', 'Truncated kind');
+  assertEqual(report.findings[2].length, 2, 'Truncated length');
+  
+  report = encoding.diagnoseUTF8(new Uint8Array([0xFF, 0xFF, 0xFF]), { maxFindings: 2 });
+  assertEqual(report.findings.length, 2, 'maxFindings should cap findings');
+  assert(report.limited, 'Capped report should be marked as limited');
+  
+  console.log('✓ diagnoseUTF8 tests passed\n');
+}
+
+// Test legacy single-byte character sets
+function testSingleByteCharsets() {
+  console.log('Testing encodeCharset/decodeCharset...');
+  
+  const bytes = encoding.encodeCharset('café €5', 'windows-1252');
+  assertArrayEqual(Array.from(new Uint8Array(bytes)), [99, 97, 102, 233, 32, 128, 53], 'windows-1252 bytes should match');
+  assertEqual(encoding.decodeCharset(bytes, 'latin1'), 'café €5', 'latin1 label should decode as windows-1252');
+  
+  const koi8 = new Uint8Array([0xF0, 0xD2, 0xC9, 0xD7, 0xC5, 0xD4]);
+  assertEqual(encoding.decodeCharset(koi8, 'koi8-r'), 'Привет', 'KOI8-R should decode Cyrillic');
+  assertArrayEqual(Array.from(new Uint8Array(encoding.encodeCharset('Привет', 'koi8-r'))), Array.from(koi8), 'KOI8-R should encode Cyrillic');
+  assertEqual(encoding.decodeCharset(new Uint8Array([0xA3, 0xF3, 0x64, 0xBC]), 'iso-8859-2'), 'Łódź', 'ISO-8859-2 should decode Polish');
+  assertEqual(encoding.decodeCharset(new Uint8Array([0xA4]), 'iso-8859-15'), '€', 'ISO-8859-15 should decode the euro sign');
+  assertEqual(encoding.decodeCharset(new Uint8Array([0x8E]), 'macintosh'), 'é', 'Macintosh should decode e-acute');
+  assertEqual(encoding.decodeCharset(new Uint8Array([0x61, 0xAA]), 'windows-1253'), 'a\uFFFD', 'Undefined bytes should be replaced');
+  
+  let threw = false;
+  try {
+    encoding.decodeCharset(new Uint8Array([0xAA]), 'windows-1253', { fatal: true });
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'Fatal decoding should throw on undefined bytes');
+  
+  threw = false;
+  try {
+    encoding.encodeCharset('你好', 'iso-8859-2');
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'Encoding unmappable characters should throw');
+  
+  assertEqual(new encoding.TextDecoder('cp1251').decode(new Uint8Array([0xCF, 0xF0, 0xE8])), 'При', 'TextDecoder should accept legacy labels');
+  
+  console.log('✓ encodeCharset/decodeCharset tests passed\n');
+}
+
+// Test CJK multi-byte character sets
+function testMultiByteCharsets() {
+  console.log('Testing CJK character sets...');
+  
+  const cases = [
+    ['shift_jis', '日本語', [0x93, 0xFA, 0x96, 0x7B, 0x8C, 0xEA]],
+    ['euc-jp', '日本語', [0xC6, 0xFC, 0xCB, 0xDC, 0xB8, 0xEC]],
+    ['iso-2022-jp', '日本語', [0x1B, 0x24, 0x42, 0x46, 0x7C, 0x4B, 0x5C, 0x38, 0x6C, 0x1B, 0x28, 0x42]],
+    ['gbk', '中文', [0xD6, 0xD0, 0xCE, 0xC4]],
+    ['gb18030', '中文😀', [0xD6, 0xD0, 0xCE, 0xC4, 0x94, 0x39, 0xFC, 0x36]],
+    ['big5', '中文', [0xA4, 0xA4, 0xA4, 0xE5]],
+    ['euc-kr', '한국어', [0xC7, 0xD1, 0xB1, 0xB9, 0xBE, 0xEE]],
+  ];
+  
+  for (const [label, text, bytes] of cases) {
+    assertArrayEqual(Array.from(new Uint8Array(encoding.encodeCharset(text, label))), bytes, `${label} encoding should match`);
+    assertEqual(encoding.decodeCharset(new Uint8Array(bytes), label, { fatal: true }), text, `${label} decoding should match`);
+  }
+  
+  // Streaming keeps partial sequences and escape state
+  const decoder = new encoding.TextDecoder('iso-2022-jp');
+  const jis = new Uint8Array(cases[2][2]);
+  let streamed = '';
+  for (let i = 0; i < jis.length; i++) {
+    streamed += decoder.decode(jis.subarray(i, i + 1), { stream: true });
+  }
+  streamed += decoder.decode();
+  assertEqual(streamed, '日本語', 'Streaming ISO-2022-JP should decode');
+  
+  let threw = false;
+  try {
+    encoding.decodeCharset(new Uint8Array([0x81, 0x20]), 'shift_jis', { fatal: true });
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'Fatal decoding should throw on invalid Shift_JIS');
+  
+  console.log('✓ CJK character set tests passed\n');
+}
+
+// Test UTF-16 and UTF-32 functions
+function testUTF16AndUTF32() {
+  console.log('Testing UTF-16 and UTF-32...');
+  
+  let bytes = encoding.encodeUTF16('hi');
+  assertArrayEqual(Array.from(new Uint8Array(bytes)), [0x68, 0x00, 0x69, 0x00], 'UTF-16LE should be the default');
+  
+  bytes = encoding.encodeUTF16('🌍', { byteOrder: 'be', bom: 'emit' });
+  assertArrayEqual(Array.from(new Uint8Array(bytes)), [0xFE, 0xFF, 0xD8, 0x3C, 0xDF, 0x0D], 'UTF-16BE with BOM should match');
+  assertEqual(encoding.decodeUTF16(bytes, { bom: 'auto' }), '🌍', 'auto should detect big endian');
+  assertEqual(encoding.decodeUTF16(bytes, { bom: 'require' }), '🌍', 'require should accept a BOM');
+  
+  let threw = false;
+  try {
+    encoding.decodeUTF16(new Uint8Array([0x68, 0x00]), { bom: 'require' });
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'require should throw without a BOM');
+  
+  threw = false;
+  try {
+    encoding.decodeUTF16(new Uint8Array([0x3C, 0xD8, 0x68, 0x00]));
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'Unpaired surrogates should throw');
+  assertEqual(encoding.decodeUTF16(new Uint8Array([0x3C, 0xD8, 0x68, 0x00]), { replace: true }), '\uFFFDh', 'replace should substitute U+FFFD');
+  
+  const text = 'Hello 🌍 你好';
+  assertEqual(encoding.decodeUTF32(encoding.encodeUTF32(text)), text, 'UTF-32LE should round-trip');
+  assertEqual(encoding.decodeUTF32(encoding.encodeUTF32(text, { byteOrder: 'be', bom: 'emit' }), { bom: 'require' }), text, 'UTF-32BE should round-trip');
+  
+  assertEqual(new encoding.TextDecoder('utf-16le').decode(new Uint8Array([0xFF, 0xFE, 0x68, 0x00])), 'h', 'TextDecoder should decode UTF-16LE');
+  
+  console.log('✓ UTF-16 and UTF-32 tests passed\n');
+}
+
+// Test transcoding between encodings
+function testTranscode() {
+  console.log('Testing transcode...');
+  
+  let result = encoding.transcode(new Uint8Array([0x93, 0xFA, 0x96, 0x7B]), 'shift_jis', 'utf-8');
+  assertEqual(encoding.decodeUTF8(result.bytes), '日本', 'Shift_JIS should transcode to UTF-8');
+  assertEqual(result.unmappable.length, 0, 'No characters should be unmappable');
+  
+  const source = encoding.encodeUTF8('Price: 5€ (日本)');
+  result = encoding.transcode(source, 'utf-8', 'iso-8859-2', { fallback: 'html' });
+  assertEqual(encoding.decodeCharset(result.bytes, 'iso-8859-2'), 'Price: 5&#8364; (&#26085;&#26412;)', 'html fallback should write character references');
+  assertArrayEqual(result.unmappable.map((u) => u.character), ['€', '日', '本'], 'Unmappable characters should be reported');
+  
+  result = encoding.transcode(source, 'utf-8', 'windows-1252', { fallback: 'replace' });
+  assertEqual(encoding.decodeCharset(result.bytes, 'windows-1252'), 'Price: 5€ (??)', 'replace fallback should write ?');
+  
+  result = encoding.transcode(source, 'utf-8', 'windows-1252', { fallback: 'skip' });
+  assertEqual(encoding.decodeCharset(result.bytes, 'windows-1252'), 'Price: 5€ ()', 'skip fallback should drop characters');
+  
+  let threw = false;
+  try {
+    encoding.transcode(source, 'utf-8', 'windows-1252');
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'The error fallback should throw');
+  
+  console.log('✓ Transcode tests passed\n');
+}
+
+// Test base64 variant options
+function testBase64Options() {
+  console.log('Testing base64 options...');
+  
+  assertEqual(encoding.encodeUTF8ToBase64('??>', { alphabet: 'url' }), 'Pz8-', 'url alphabet should use - and _');
+  assertEqual(encoding.encodeUTF8ToBase64('hi', { padding: 'none' }), 'aGk', 'padding none should omit =');
+  assertEqual(encoding.decodeUTF8FromBase64('eyJhbGciOiJIUzI1NiJ9', { alphabet: 'url', padding: 'optional' }), '{"alg":"HS256"}', 'JWT segments should decode');
+  assertEqual(encoding.decodeUTF8FromBase64(' aGVs\tbG8=\n', { lenient: true }), 'hello', 'lenient should ignore whitespace');
+  
+  const wrapped = encoding.encodeUTF8ToBase64('a'.repeat(60), { mime: true });
+  assertEqual(wrapped.indexOf('\r\n'), 76, 'mime should wrap at 76 columns');
+  assertEqual(encoding.decodeUTF8FromBase64(wrapped), 'a'.repeat(60), 'Wrapped base64 should decode');
+  
+  let threw = false;
+  try {
+    encoding.decodeUTF8FromBase64('aGl=', { strict: true });
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'strict should reject non-canonical trailing bits');
+  
+  console.log('✓ Base64 options tests passed\n');
+}
+
+// Test binary base64 functions
+function testBinaryBase64() {
+  console.log('Testing binary base64...');
+  
+  const bytes = new Uint8Array([0x89, 0x50, 0x4E, 0x47, 0x00, 0xFF]);
+  const encoded = encoding.encodeBase64(bytes);
+  assertEqual(encoded, 'iVBORwD/', 'Binary data should encode to base64');
+  
+  const decoded = encoding.decodeBase64(encoded);
+  assert(decoded instanceof ArrayBuffer, 'decodeBase64 should return an ArrayBuffer');
+  assertArrayEqual(Array.from(new Uint8Array(decoded)), Array.from(bytes), 'Binary data should round-trip');
+  
+  assertEqual(encoding.encodeBase64(bytes.buffer, { alphabet: 'url' }), 'iVBORwD_', 'ArrayBuffer input should use url alphabet');
+  assertEqual(encoding.encodeBase64(bytes.subarray(4)), 'AP8=', 'Subarrays should encode only their view');
+  
+  console.log('✓ Binary base64 tests passed\n');
+}
+
+// Test hex encoding and decoding
+function testHex() {
+  console.log('Testing hex...');
+  
+  const bytes = new Uint8Array([0xDE, 0xAD, 0xBE, 0xEF]);
+  assertEqual(encoding.encodeHex(bytes), 'deadbeef', 'Bytes should encode to lower-case hex');
+  assertEqual(encoding.encodeHex(bytes, { case: 'upper', separator: 'colon' }), 'DE:AD:BE:EF', 'Case and separator options should apply');
+  assertEqual(encoding.encodeHex(bytes, { separator: 'space', prefix: true }), '0xde 0xad 0xbe 0xef', 'Prefix should be written per byte with a separator');
+  
+  const decoded = encoding.decodeHex('de ad\nbe ef', { lenient: true });
+  assert(decoded instanceof ArrayBuffer, 'decodeHex should return an ArrayBuffer');
+  assertArrayEqual(Array.from(new Uint8Array(decoded)), Array.from(bytes), 'Lenient hex should decode');
+  
+  assertEqual(encoding.encodeUTF8ToHex('Hé', { prefix: true }), '0x48c3a9', 'Text should encode to hex');
+  assertEqual(encoding.decodeUTF8FromHex('48:C3:A9', { separator: 'colon' }), 'Hé', 'Hex should decode to text');
+  
+  let threw = false;
+  try {
+    encoding.decodeHex('de ad');
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'Whitespace should be rejected without lenient');
+  
+  console.log('✓ Hex tests passed\n');
+}
+
+// Test base32 variants
+function testBase32() {
+  console.log('Testing base32...');
+  
+  const foobar = encoding.encodeUTF8('foobar');
+  assertEqual(encoding.encodeBase32(foobar), 'MZXW6YTBOI======', 'Standard base32 should match RFC 4648');
+  assertEqual(encoding.encodeBase32(foobar, { variant: 'hex', padding: 'none' }), 'CPNMUOJ1E8', 'Extended hex base32 should match RFC 4648');
+  assertEqual(encoding.encodeBase32(foobar, { variant: 'crockford', checksum: true }), 'CSQPYRK1E8R', 'Crockford base32 should append a check symbol');
+  assertEqual(encoding.encodeBase32(new Uint8Array([0xF0, 0xBF, 0xC7]), { variant: 'zbase32' }), '6n9hq', 'z-base-32 should match the spec');
+  
+  let decoded = encoding.decodeBase32('csqp-yrkl-e8-r', { variant: 'crockford', checksum: true });
+  assert(decoded instanceof ArrayBuffer, 'decodeBase32 should return an ArrayBuffer');
+  assertEqual(encoding.decodeUTF8(new Uint8Array(decoded)), 'foobar', 'Crockford decoding should tolerate case, hyphens and confusables');
+  
+  decoded = encoding.decodeBase32('jbsw y3dp ehpk 3pxp', { lenient: true, padding: 'optional' });
+  assertEqual(new Uint8Array(decoded).length, 10, 'Lenient decoding should accept TOTP secrets');
+  
+  let threw = false;
+  try {
+    encoding.decodeBase32('CSQPYRK1E8S', { variant: 'crockford', checksum: true });
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'A wrong check symbol should throw');
+  
+  console.log('✓ Base32 tests passed\n');
+}
+
+// Test Base58 and Base58Check
+function testBase58() {
+  console.log('Testing Base58...');
+  
+  const hello = encoding.encodeUTF8('Hello World!');
+  assertEqual(encoding.encodeBase58(hello), '2NEpo7TZRRrLZSi2U', 'Base58 should use the Bitcoin alphabet');
+  assertEqual(encoding.encodeBase58(hello, { alphabet: 'flickr' }), '2nePN7syqqRkyrH2t', 'Base58 should support the Flickr alphabet');
+  assertEqual(encoding.decodeUTF8(encoding.decodeBase58('2NEpo7TZRRrLZSi2U')), 'Hello World!', 'Base58 should decode');
+  const decoded = encoding.decodeBase58('11233QC4');
+  assert(decoded instanceof ArrayBuffer, 'decodeBase58 should return an ArrayBuffer');
+  assertArrayEqual(Array.from(new Uint8Array(decoded)), [0, 0, 0x28, 0x7F, 0xB4, 0xCD], 'Leading zeros should be kept');
+  assertThrows(() => encoding.encodeBase58('Hello'), 'encodeBase58 should reject strings');
+  
+  const check = encoding.decodeBase58Check('1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa');
+  assertEqual(check.version, 0, 'Base58Check should return the version byte');
+  assert(check.payload instanceof ArrayBuffer, 'Base58Check payload should be an ArrayBuffer');
+  assertEqual(encoding.encodeBase58Check(check.payload, check.version), '1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa', 'Base58Check should round-trip');
+  
+  let threw = false;
+  try {
+    encoding.decodeBase58Check('1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb');
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'A wrong checksum should throw');
+  
+  console.log('✓ Base58 tests passed\n');
+}
+
+// Test Bech32 and Bech32m
+function testBech32() {
+  console.log('Testing Bech32...');
+  
+  const program = encoding.decodeHex('751e76e8199196d454941c45d1b3a323f1433bd6');
+  const words = new Uint8Array(encoding.convertBits(program, 8, 5, true));
+  const address = encoding.encodeBech32('bc', new Uint8Array([0, ...words]), { words: true });
+  assertEqual(address, 'bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4', 'SegWit v0 address should match BIP 173');
+  
+  const decoded = encoding.decodeBech32(address.toUpperCase(), { words: true });
+  assertEqual(decoded.hrp, 'bc', 'HRP should be decoded in lower case');
+  assertEqual(decoded.variant, 'bech32', 'Variant should be detected');
+  assert(decoded.words instanceof ArrayBuffer, 'Words should be an ArrayBuffer');
+  assertEqual(decoded.data, null, 'Data should be null when words are requested');
+  assertEqual(encoding.convertBits(new Uint8Array(decoded.words).subarray(1), 5, 8, false).byteLength, 20, 'Witness program should regroup to 20 bytes');
+  assertThrows(() => encoding.encodeBech32('bc', [0, 1, 2], { words: true }), 'encodeBech32 should reject plain arrays');
+  
+  const m = encoding.encodeBech32('test', encoding.encodeUTF8('hello'), { variant: 'bech32m' });
+  const result = encoding.decodeBech32(m);
+  assertEqual(result.variant, 'bech32m', 'Bech32m should be detected');
+  assertEqual(encoding.decodeUTF8(result.data), 'hello', 'Bech32m data should round-trip');
+  
+  let checksumError = '';
+  try {
+    encoding.decodeBech32('bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5');
+  } catch (e) {
+    checksumError = String(e);
+  }
+  assert(checksumError.includes('invalid bech32 checksum'), 'A wrong checksum should be reported as such');
+  
+  let characterError = '';
+  try {
+    encoding.decodeBech32('bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3tb');
+  } catch (e) {
+    characterError = String(e);
+  }
+  assert(characterError.includes('invalid bech32 character'), 'A character outside the alphabet should be reported as such');
+  
+  console.log('✓ Bech32 tests passed\n');
+}
+
+// Test base85 variants
+function testBase85() {
+  console.log('Testing base85...');
+  
+  const hello = encoding.encodeUTF8('hello');
+  assertEqual(encoding.encodeBase85(hello), 'BOu!rDZ', 'Ascii85 should be the default');
+  assertEqual(encoding.encodeBase85(hello, { delimiters: true }), '<~BOu!rDZ~>', 'Adobe delimiters should be added');
+  assertEqual(encoding.encodeBase85(new Uint8Array([0, 0, 0, 0])), 'z', 'Zero groups should use z');
+  assertEqual(encoding.encodeBase85(new Uint8Array([0x86, 0x4F, 0xD2, 0x6F, 0xB5, 0x59, 0xF7, 0x5B]), { variant: 'z85' }), 'HelloWorld', 'Z85 should match the spec');
+  assertEqual(encoding.encodeBase85(hello, { variant: 'rfc1924' }), 'Xk~0{Zv', 'RFC 1924 alphabet should be supported');
+  
+  const decoded = encoding.decodeBase85('<~BOu!rDZ~>');
+  assert(decoded instanceof ArrayBuffer, 'decodeBase85 should return an ArrayBuffer');
+  assertEqual(encoding.decodeUTF8(new Uint8Array(decoded)), 'hello', 'Ascii85 should decode');
+  
+  let threw = false;
+  try {
+    encoding.encodeBase85(new Uint8Array(3), { variant: 'z85' });
+  } catch (e) {
+    threw = true;
+  }
+  assert(threw, 'Z85 should reject lengths that are not a multiple of 4');
+  
+  console.log('✓ Base85 tests passed\n');
+}
+
+// Test Base45
+function testBase45() {
+  console.log('Testing Base45...');
+  
+  assertEqual(encoding.encodeUTF8ToBase45('Hello!!'), '%69 VD92EX0', 'Base45 should match RFC 9285');
+  assertEqual(encoding.decodeUTF8FromBase45('UJCLQE7W581'), 'base-45', 'Base45 should decode to text');
+  
+  const encoded = encoding.encodeBase45(new Uint8Array([0x41, 0x42, 0xFF]));
+  assertEqual(encoded, 'BB8U5', 'Bytes should encode to Base45');
+  const decoded = encoding.decodeBase45(encoded);
+  assert(decoded instanceof ArrayBuffer, 'decodeBase45 should return an ArrayBuffer');
+  assertArrayEqual(Array.from(new Uint8Array(decoded)), [0x41, 0x42, 0xFF], 'Bytes should round-trip');
+  
+  let message = '';
+  try {
+    encoding.decodeUTF8FromBase45('QED8wEX0');
+  } catch (e) {
+    message = String(e);
+  }
+  assert(message.includes('offset 4'), 'Errors should report the offending position');
+  
+  console.log('✓ Base45 tests passed\n');
+}
+
+function testRadix() {
+  console.log('\n=== Testing Arbitrary Radix ===');
+
+  const encoded = encoding.encodeUTF8ToRadix('Hello World');
+  if (encoded !== '73xPuGYmWKgR29m' || encoding.decodeUTF8FromRadix(encoded) !== 'Hello World') {
+    throw new Error(`Base62 round trip failed: ${encoded}`);
+  }
+  const zeros = encoding.encodeRadix(new Uint8Array([0, 0, 1, 2]));
+  if (zeros !== '004a' || new Uint8Array(encoding.decodeRadix(zeros)).join(',') !== '0,0,1,2') {
+    throw new Error(`Leading zeros were not preserved: ${zeros}`);
+  }
+  const id = encoding.encodeRadixInteger(2n ** 64n, { alphabet: 'base36' });
+  if (id !== '3w5e11264sgsg' || encoding.decodeRadixInteger(id.toUpperCase(), { alphabet: 'base36' }) !== 2n ** 64n) {
+    throw new Error(`Base36 integer round trip failed: ${id}`);
+  }
+  try {
+    encoding.encodeRadix(new Uint8Array([1]), { alphabet: 'abca' });
+    throw new Error('Duplicate alphabet characters were accepted');
+  } catch (e) {
+    if (!e.message.includes('duplicate character')) throw e;
+  }
+  console.log('✓ Arbitrary radix tests passed');
+}
+
+function testUUEncode() {
+  console.log('\n=== Testing uuencode and xxencode ===');
+
+  const block = encoding.encodeUU(encoding.encodeUTF8('Cat'), 'cat.txt');
+  if (block !== 'begin 644 cat.txt\n#0V%T\n`\nend\n') {
+    throw new Error(`Unexpected uuencoded block: ${JSON.stringify(block)}`);
+  }
+  const decoded = encoding.decodeUU(block.replace(/\n/g, '\r\n'));
+  if (decoded.mode !== '644' || decoded.filename !== 'cat.txt' || encoding.decodeUTF8(decoded.data) !== 'Cat') {
+    throw new Error(`uudecode failed: ${JSON.stringify(decoded)}`);
+  }
+  const data = new Uint8Array(100).map((_, i) => i * 7);
+  const xx = encoding.decodeXX(encoding.encodeXX(data, 'data.bin', { mode: '600' }));
+  if (xx.mode !== '600' || new Uint8Array(xx.data).join(',') !== data.join(',')) {
+    throw new Error('xxencode round trip failed');
+  }
+  try {
+    encoding.decodeUU('begin 644 cat.txt\n#0V%T\n');
+    throw new Error('A block without an end line was accepted');
+  } catch (e) {
+    if (!e.message.includes('missing "end" line')) throw e;
+  }
+  console.log('✓ uuencode and xxencode tests passed');
+}
+
+function testStringToBytes() {
+  console.log('\n=== Testing Latin-1 round trips ===');
+
+  const all = new Uint8Array(256).map((_, i) => i);
+  const text = encoding.bytesToString(all);
+  if (text.length !== 256 || text.charCodeAt(0xE6) !== 0xE6 || text.charCodeAt(0xFF) !== 0xFF) {
+    throw new Error('bytesToString did not decode ISO-8859-1');
+  }
+  if (new Uint8Array(encoding.stringToBytes(text)).join(',') !== Array.from(all).join(',')) {
+    throw new Error('stringToBytes did not round-trip every byte');
+  }
+  if (encoding.stringToBytes('a€b', { fallback: 'html' }).byteLength !== 9) {
+    throw new Error('HTML fallback was not applied');
+  }
+  try {
+    encoding.stringToBytes('你好');
+    throw new Error('Characters above U+00FF were accepted');
+  } catch (e) {
+    if (!e.message.includes('character cannot be encoded')) throw e;
+  }
+  console.log('✓ Latin-1 round trip tests passed');
+}
+
+function testBinaryStrings() {
+  console.log('\n=== Testing binary strings ===');
+
+  let binary = '';
+  for (let i = 0; i < 256; i++) binary += String.fromCharCode(i);
+  const buffer = encoding.binaryStringToArrayBuffer(binary);
+  if (!(buffer instanceof ArrayBuffer) || buffer.byteLength !== 256) {
+    throw new Error('binaryStringToArrayBuffer did not return a 256 byte ArrayBuffer');
+  }
+  const bytes = encoding.binaryStringToUint8Array(binary);
+  if (!(bytes instanceof Uint8Array) || bytes[0xFF] !== 0xFF) {
+    throw new Error('binaryStringToUint8Array returned the wrong bytes');
+  }
+  if (encoding.arrayBufferToBinaryString(buffer) !== binary) {
+    throw new Error('arrayBufferToBinaryString did not round-trip');
+  }
+  if (encoding.arrayBufferToBinaryString(bytes.subarray(0x41, 0x44)) !== 'ABC') {
+    throw new Error('arrayBufferToBinaryString ignored the view region');
+  }
+  try {
+    encoding.binaryStringToArrayBuffer('abĀ');
+    throw new Error('A character above 0xFF was accepted');
+  } catch (e) {
+    if (!e.message.includes('index 2')) throw e;
+  }
+  console.log('✓ Binary string tests passed');
+}
+
+function testBuffer() {
+  console.log('\n=== Testing Buffer ===');
+  const { Buffer } = encoding;
+
+  const buf = Buffer.from('68656c6c6f', 'hex');
+  if (buf.toString() !== 'hello' || buf.toString('base64') !== 'aGVsbG8=' || !(buf instanceof Uint8Array)) {
+    throw new Error('Buffer.from hex round trip failed');
+  }
+  const header = Buffer.alloc(8);
+  header.writeUInt32BE(0xCAFEBABE, 0);
+  header.writeUInt16LE(42, 4);
+  if (header.toString('hex') !== 'cafebabe2a000000' || header.readUInt32BE(0) !== 0xCAFEBABE) {
+    throw new Error(`Buffer accessors failed: ${header.toString('hex')}`);
+  }
+  const frame = Buffer.concat([header, Buffer.from('payload')]);
+  if (frame.length !== 15 || frame.indexOf('load') !== 11 || frame.slice(8).toString() !== 'payload') {
+    throw new Error('Buffer.concat, indexOf or slice failed');
+  }
+  if (!Buffer.isBuffer(frame.subarray(1)) || Buffer.compare(Buffer.from('a'), Buffer.from('b')) !== -1) {
+    throw new Error('Buffer.isBuffer or Buffer.compare failed');
+  }
+  if (!(frame instanceof Buffer) || !(frame.map((x) => x) instanceof Buffer) || !(new Buffer(2) instanceof Buffer)) {
+    throw new Error('Buffer is not a constructor of its buffers');
+  }
+  if (JSON.stringify(Buffer.from([1, 2])) !== '{"type":"Buffer","data":[1,2]}') {
+    throw new Error('Buffer#toJSON failed');
+  }
+  try {
+    header.readUInt32BE(6);
+    throw new Error('An out-of-range read was accepted');
+  } catch (e) {
+    if (!e.message.includes('out of range')) throw e;
+  }
+  console.log('✓ Buffer tests passed');
+}
+
+
+function testNormalization() {
+  console.log('\n=== Testing Unicode Normalization ===');
+  const composed = 'caf\u00e9';
+  const decomposed = 'cafe\u0301';
+
+  if (encoding.normalize(decomposed) !== composed || encoding.normalize(composed, 'NFD') !== decomposed) {
+    throw new Error('normalize failed');
+  }
+  if (encoding.countUTF8Bytes(encoding.normalize(decomposed, 'NFC')) !== encoding.countUTF8Bytes(composed)) {
+    throw new Error('Normalized byte counts differ');
+  }
+  if (encoding.isNormalized(decomposed, 'NFC') || !encoding.isNormalized(decomposed, 'NFD')) {
+    throw new Error('isNormalized failed');
+  }
+  if (!encoding.equalNormalized(composed, decomposed, 'NFC') || encoding.equalNormalized('ﬁ', 'fi', 'NFC')) {
+    throw new Error('equalNormalized failed');
+  }
+  if (encoding.quickCheckNormalization(decomposed, 'NFC') !== 'maybe') {
+    throw new Error('quickCheckNormalization failed');
+  }
+  try {
+    encoding.normalize('x', 'NFX');
+    throw new Error('An unknown normalization form was accepted');
+  } catch (e) {
+    if (!e.message.includes('invalid option')) throw e;
+  }
+  console.log('✓ Unicode normalization tests passed');
+}
+
+
+function testGraphemes() {
+  console.log('\n=== Testing Grapheme Clusters ===');
+  const family = '\u{1F468}\u200D\u{1F469}\u200D\u{1F467}\u200D\u{1F466}';
+
+  if (encoding.countUTF8Runes(family) !== 7 || encoding.countGraphemes(family) !== 1) {
+    throw new Error('countGraphemes failed for the family emoji');
+  }
+  if (encoding.countGraphemes('cafe\u0301') !== 4 || encoding.splitGraphemes('e\u0301🇩🇪').length !== 2) {
+    throw new Error('Grapheme splitting failed');
+  }
+  if (encoding.substringGraphemes('ab' + family + 'cd', 2, 3) !== family) {
+    throw new Error('substringGraphemes split a cluster');
+  }
+  if (encoding.substringGraphemes('abcdef', 2, undefined) !== 'cdef') {
+    throw new Error('substringGraphemes did not treat an undefined end as the end of the text');
+  }
+  if (encoding.reverseGraphemes('ab🇩🇪') !== '🇩🇪ba') {
+    throw new Error('reverseGraphemes failed');
+  }
+  if (!/^\d+\.\d+\.\d+$/.test(encoding.unicodeVersion())) {
+    throw new Error(`Unexpected Unicode version: ${encoding.unicodeVersion()}`);
+  }
+  console.log('✓ Grapheme cluster tests passed');
+}
+
+
+function testSegmentation() {
+  console.log('\n=== Testing Word and Sentence Segmentation ===');
+  const text = 'Olá 👋 mundo. 你好!';
+
+  const words = encoding.segmentWords(text);
+  const wordLike = words.filter((w) => w.isWordLike).map((w) => w.segment).join(',');
+  if (wordLike !== 'Olá,mundo,你,好') {
+    throw new Error(`Unexpected word-like segments: ${wordLike}`);
+  }
+  for (const w of words) {
+    if (text.slice(w.utf16Offset, w.utf16Offset + w.utf16Length) !== w.segment) {
+      throw new Error(`UTF-16 offsets of "${w.segment}" do not match the string`);
+    }
+  }
+  const emoji = words.find((w) => w.segment === '👋');
+  if (emoji.byteOffset !== 5 || emoji.byteLength !== 4 || emoji.utf16Length !== 2) {
+    throw new Error('Byte offsets of the emoji are wrong');
+  }
+
+  const sentences = encoding.segmentSentences('Hello world. How are you? Fine!').map((s) => s.segment);
+  if (sentences.length !== 3 || sentences[1] !== 'How are you? ') {
+    throw new Error(`Unexpected sentences: ${JSON.stringify(sentences)}`);
+  }
+  console.log('✓ Word and sentence segmentation tests passed');
+}
+
+
+function testLineBreaking() {
+  console.log('\n=== Testing Line Breaking and Wrapping ===');
+  const lines = encoding.wrap('The quick brown fox jumps over the lazy dog', 10);
+  if (lines.join('|') !== 'The quick|brown fox|jumps over|the lazy|dog') {
+    throw new Error(`Unexpected wrap: ${JSON.stringify(lines)}`);
+  }
+  const wide = encoding.wrap('你好世界，欢迎光临', 6);
+  if (wide.join('|') !== '你好世|界，欢|迎光临') {
+    throw new Error(`Wide characters wrapped wrongly: ${JSON.stringify(wide)}`);
+  }
+  const family = '\u{1F468}\u200D\u{1F469}\u200D\u{1F467}\u200D\u{1F466}';
+  // Concatenate rather than use repeat(), which mangles surrogate pairs in sobek.
+  if (encoding.wrap(family + family + family, 5).join('|') !== family + family + '|' + family) {
+    throw new Error('wrap split a grapheme cluster');
+  }
+  const segments = encoding.segmentLineBreaks('well-known 你好\nok');
+  if (segments.map((s) => s.segment).join('|') !== 'well-|known |你|好\n|ok' || !segments[3].mustBreak) {
+    throw new Error('segmentLineBreaks failed');
+  }
+  try {
+    encoding.wrap('text', 0);
+    throw new Error('A zero width was accepted');
+  } catch (e) {
+    if (!e.message.includes('width must be at least 1')) throw e;
+  }
+  console.log('✓ Line breaking and wrapping tests passed');
+}
+
+
+function testDisplayWidth() {
+  console.log('\n=== Testing Display Width ===');
+  const name = 'カフェ・ラテ';
+
+  if (encoding.countUTF8Runes(name) !== 6 || encoding.displayWidth(name) !== 12) {
+    throw new Error('displayWidth failed for wide characters');
+  }
+  if (encoding.displayWidth('cafe\u0301') !== 4) {
+    throw new Error('Combining marks should take no columns');
+  }
+  if (encoding.displayWidth('±°') !== 2 || encoding.displayWidth('±°', { ambiguousWidth: 2 }) !== 4) {
+    throw new Error('ambiguousWidth was not honored');
+  }
+  if (encoding.padToWidth(name, 14) !== name + '  ' || encoding.padToWidth('7', 3, { align: 'right', fill: '0' }) !== '007') {
+    throw new Error('padToWidth failed');
+  }
+  if (encoding.truncateToWidth(name, 7) !== 'カフェ…' || encoding.truncateToWidth('Hello, world', 5, { ellipsis: '' }) !== 'Hello') {
+    throw new Error('truncateToWidth failed');
+  }
+  try {
+    encoding.truncateToWidth('Hello', 2, { ellipsis: '...' });
+    throw new Error('A width narrower than the ellipsis was accepted');
+  } catch (e) {
+    if (!e.message.includes('narrower than the ellipsis')) throw e;
+  }
+  console.log('✓ Display width tests passed');
 }
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestEncodeUTF8(t *testing.T) {
@@ -225,6 +226,103 @@ func TestDecodeUTF8FromBase64(t *testing.T) {
 	}
 }
 
+func TestDecodeUTF8WithReplacement(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name         string
+		input        []byte
+		expected     string
+		replacements int
+	}{
+		{
+			name:         "empty bytes",
+			input:        []byte{},
+			expected:     "",
+			replacements: 0,
+		},
+		{
+			name:         "valid unicode",
+			input:        []byte("Hello 🌍"),
+			expected:     "Hello 🌍",
+			replacements: 0,
+		},
+		{
+			name:         "invalid start byte",
+			input:        []byte{'a', 0xFF, 'b'},
+			expected:     "a\uFFFDb",
+			replacements: 1,
+		},
+		{
+			name:         "truncated sequence at end",
+			input:        []byte{'o', 'k', 0xF0, 0x9F, 0x8C},
+			expected:     "ok\uFFFD",
+			replacements: 1,
+		},
+		{
+			name:         "overlong encoding",
+			input:        []byte{0xC0, 0xAF},
+			expected:     "\uFFFD\uFFFD",
+			replacements: 2,
+		},
+		{
+			name:         "surrogate",
+			input:        []byte{0xED, 0xA0, 0x80},
+			expected:     "\uFFFD\uFFFD\uFFFD",
+			replacements: 3,
+		},
+		{
+			// Example from the Unicode Standard, section 3.9 "U+FFFD Substitution of Maximal Subparts".
+			name:         "maximal subparts",
+			input:        []byte{0x61, 0xF1, 0x80, 0x80, 0xE1, 0x80, 0xC2, 0x62, 0x80, 0x63, 0x80, 0xBF, 0x64},
+			expected:     "a\uFFFD\uFFFD\uFFFDb\uFFFDc\uFFFD\uFFFDd",
+			replacements: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.DecodeUTF8WithReplacement(tt.input)
+			if err != nil {
+				t.Fatalf("DecodeUTF8WithReplacement() unexpected error: %v", err)
+			}
+			if result.Text != tt.expected {
+				t.Errorf("DecodeUTF8WithReplacement() text = %q, want %q", result.Text, tt.expected)
+			}
+			if result.Replacements != tt.replacements {
+				t.Errorf("DecodeUTF8WithReplacement() replacements = %d, want %d", result.Replacements, tt.replacements)
+			}
+		})
+	}
+
+	_, err := te.DecodeUTF8WithReplacement(make([]byte, MaxInputSize+1))
+	if err == nil {
+		t.Error("DecodeUTF8WithReplacement() expected error for oversized input")
+	}
+}
+
+func TestDecodeUTF8FromBase64WithReplacement(t *testing.T) {
+	te := &TextEncoding{}
+
+	result, err := te.DecodeUTF8FromBase64WithReplacement(base64.StdEncoding.EncodeToString([]byte{'o', 'k', 0xFF, 0xFE}))
+	if err != nil {
+		t.Fatalf("DecodeUTF8FromBase64WithReplacement() unexpected error: %v", err)
+	}
+	if result.Text != "ok\uFFFD\uFFFD" || result.Replacements != 2 {
+		t.Errorf("DecodeUTF8FromBase64WithReplacement() = %q (%d), want %q (2)", result.Text, result.Replacements, "ok\uFFFD\uFFFD")
+	}
+
+	result, err = te.DecodeUTF8FromBase64WithReplacement("")
+	if err != nil || result.Text != "" || result.Replacements != 0 {
+		t.Errorf("DecodeUTF8FromBase64WithReplacement(\"\") = %+v, %v", result, err)
+	}
+
+	_, err = te.DecodeUTF8FromBase64WithReplacement("invalid base64!@#")
+	if err == nil {
+		t.Error("DecodeUTF8FromBase64WithReplacement() expected error for invalid base64")
+	}
+}
+
 func TestCountUTF8Bytes(t *testing.T) {
 	te := &TextEncoding{}
 
@@ -622,8 +720,8 @@ func TestBytesToString(t *testing.T) {
 	if err != nil {
 		t.Errorf("BytesToString() error = %v", err)
 	}
-	// The function treats bytes as raw bytes, so we expect the raw byte values
-	expected := string([]byte{72, 101, 108, 108, 111, 32, 230, 248, 229})
+	// Each byte is decoded as the ISO-8859-1 character with the same code point
+	expected := "Hello æøå"
 	if result != expected {
 		t.Errorf("BytesToString() = %q, want %q", result, expected)
 	}
@@ -634,7 +732,7 @@ func TestBytesToString(t *testing.T) {
 	if err != nil {
 		t.Errorf("BytesToString() error = %v", err)
 	}
-	expected = string([]byte{0x00, 0xFF, 0x7F, 0x80})
+	expected = "\x00\u00ff\x7f\u0080"
 	if result != expected {
 		t.Errorf("BytesToString() = %q, want %q", result, expected)
 	}
@@ -648,8 +746,8 @@ func TestBytesToString(t *testing.T) {
 	if err != nil {
 		t.Errorf("BytesToString() error = %v", err)
 	}
-	if len(result) != len(largeBytes) {
-		t.Errorf("BytesToString() length = %d, want %d", len(result), len(largeBytes))
+	if n := utf8.RuneCountInString(result); n != len(largeBytes) {
+		t.Errorf("BytesToString() length = %d, want %d", n, len(largeBytes))
 	}
 
 	// Test maximum size input
@@ -674,6 +772,91 @@ func TestBytesToString(t *testing.T) {
 	}
 }
 
+func TestStringToBytes(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        Latin1Options
+		expected    []byte
+		expectError string
+	}{
+		{name: "empty", input: "", expected: []byte{}},
+		{name: "ascii", input: "Hello", expected: []byte("Hello")},
+		{name: "latin-1", input: "Hello æøå", expected: []byte{72, 101, 108, 108, 111, 32, 230, 248, 229}},
+		{name: "binary", input: "\x00\u00ff\x7f\u0080", expected: []byte{0x00, 0xFF, 0x7F, 0x80}},
+		{name: "above U+00FF", input: "a€b", expectError: ErrUnmappableCharacter + ": '€' (U+20AC) at index 1"},
+		{name: "index counts characters", input: "é€", expectError: "at index 1 is not"},
+		{name: "replace", input: "a€b", opts: Latin1Options{Fallback: FallbackReplace}, expected: []byte("a?b")},
+		{name: "html", input: "a€b", opts: Latin1Options{Fallback: FallbackHTML}, expected: []byte("a&#8364;b")},
+		{name: "skip", input: "a€b", opts: Latin1Options{Fallback: FallbackSkip}, expected: []byte("ab")},
+		{name: "unknown fallback", input: "a", opts: Latin1Options{Fallback: "ignore"}, expectError: ErrInvalidOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.opts.encode(tt.input)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("encode(%q) error = %v, want %q", tt.input, err, tt.expectError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("encode(%q) unexpected error: %v", tt.input, err)
+			}
+			if string(result) != string(tt.expected) {
+				t.Errorf("encode(%q) = % X, want % X", tt.input, result, tt.expected)
+			}
+		})
+	}
+
+	// Every byte value round-trips through BytesToString.
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	text, err := TextEncoding{}.BytesToString(all)
+	if err != nil {
+		t.Fatalf("BytesToString() unexpected error: %v", err)
+	}
+	back, err := Latin1Options{}.encode(text)
+	if err != nil {
+		t.Fatalf("encode() unexpected error: %v", err)
+	}
+	if string(back) != string(all) {
+		t.Errorf("encode(BytesToString(x)) = % X, want % X", back, all)
+	}
+}
+
+func TestLatin1JS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const text = encoding.bytesToString(new Uint8Array([0x48, 0x69, 0xE6, 0xFF, 0x80]));
+		const codes = Array.from(text).map((c) => c.charCodeAt(0)).join(',');
+		const bytes = encoding.stringToBytes(text);
+		const back = new Uint8Array(bytes).join(',');
+		const replaced = new Uint8Array(encoding.stringToBytes('€5', { fallback: 'replace' })).join(',');
+		return [text.length, codes, bytes instanceof ArrayBuffer, back, replaced].join('|');
+	})()`).String()
+	expected := "5|72,105,230,255,128|true|72,105,230,255,128|63,53"
+	if result != expected {
+		t.Errorf("latin-1 = %q, want %q", result, expected)
+	}
+
+	if _, err := rt.RunString(`encoding.stringToBytes('你好')`); err == nil {
+		t.Error("stringToBytes() expected error for characters above U+00FF")
+	}
+	if _, err := rt.RunString(`encoding.stringToBytes('é€')`); err == nil || !strings.Contains(err.Error(), "at index 1 ") {
+		t.Errorf("stringToBytes() error = %v, want the JavaScript index 1", err)
+	}
+	for _, script := range []string{`encoding.stringToBytes()`, `encoding.stringToBytes(null)`} {
+		if _, err := rt.RunString(script); err == nil || !strings.Contains(err.Error(), ErrNilInput) {
+			t.Errorf("%s error = %v, want %q", script, err, ErrNilInput)
+		}
+	}
+}
+
 func BenchmarkTextEncoding(b *testing.B) {
 	te := &TextEncoding{}
 
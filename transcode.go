@@ -0,0 +1,161 @@
+package text_encoding
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+	"golang.org/x/text/transform"
+)
+
+// Fallback policies for characters the target encoding of Transcode cannot represent.
+const (
+	// FallbackError fails on the first unmappable character. It is the default.
+	FallbackError = "error"
+	// FallbackReplace writes '?' in place of the character.
+	FallbackReplace = "replace"
+	// FallbackHTML writes an HTML numeric character reference such as "&#12354;".
+	FallbackHTML = "html"
+	// FallbackSkip drops the character.
+	FallbackSkip = "skip"
+)
+
+// TranscodeOptions configures Transcode.
+// With Fatal set, source bytes that do not map to a character are an error
+// instead of being replaced with U+FFFD.
+type TranscodeOptions struct {
+	Fallback string `js:"fallback"`
+	Fatal    bool   `js:"fatal"`
+}
+
+// UnmappableCharacter is a character the target encoding could not represent.
+// Offset is the byte offset of the character in the UTF-8 form of the text.
+type UnmappableCharacter struct {
+	Character string `js:"character"`
+	CodePoint int    `js:"codePoint"`
+	Offset    int    `js:"offset"`
+}
+
+// TranscodeResult is the result of Transcode.
+type TranscodeResult struct {
+	Bytes      sobek.ArrayBuffer     `js:"bytes"`
+	Unmappable []UnmappableCharacter `js:"unmappable"`
+}
+
+// fallbackPolicy resolves a fallback option, which defaults to FallbackError.
+func fallbackPolicy(fallback string) (string, error) {
+	switch policy := strings.ToLower(fallback); policy {
+	case "":
+		return FallbackError, nil
+	case FallbackError, FallbackReplace, FallbackHTML, FallbackSkip:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%s: fallback must be one of %q, %q, %q or %q, got %q",
+			ErrInvalidOption, FallbackError, FallbackReplace, FallbackHTML, FallbackSkip, fallback)
+	}
+}
+
+// repertoireError is implemented by the errors x/text encoders return for
+// characters outside the encoding's repertoire.
+type repertoireError interface {
+	Replacement() byte
+}
+
+// encodeWithFallback converts UTF-8 text to the character set, applying the fallback
+// policy to every character the character set cannot represent.
+func (cs *charset) encodeWithFallback(text, fallback string) ([]byte, []UnmappableCharacter, error) {
+	unmappable := []UnmappableCharacter{}
+	if cs.enc == nil {
+		return []byte(text), unmappable, nil
+	}
+
+	enc := cs.enc.NewEncoder()
+	src := []byte(text)
+	buf := make([]byte, 1024)
+	out := make([]byte, 0, len(src))
+	for pos := 0; ; {
+		nDst, nSrc, err := enc.Transform(buf, src[pos:], true)
+		out = append(out, buf[:nDst]...)
+		pos += nSrc
+		if err == nil {
+			return out, unmappable, nil
+		}
+		if errors.Is(err, transform.ErrShortDst) {
+			continue
+		}
+		if _, ok := err.(repertoireError); !ok {
+			return nil, nil, err
+		}
+
+		// The encoder has returned to its initial state, so the fallback can be
+		// written as ASCII and encoding resumed after the character.
+		r, size := utf8.DecodeRune(src[pos:])
+		if fallback == FallbackError {
+			return nil, nil, fmt.Errorf("%s: %q (U+%04X) at offset %d is not representable in %s",
+				ErrUnmappableCharacter, r, r, pos, cs.name)
+		}
+		unmappable = append(unmappable, UnmappableCharacter{
+			Character: string(r),
+			CodePoint: int(r),
+			Offset:    pos,
+		})
+		switch fallback {
+		case FallbackReplace:
+			out = append(out, '?')
+		case FallbackHTML:
+			out = fmt.Appendf(out, "&#%d;", r)
+		}
+		pos += size
+	}
+}
+
+// Transcode converts bytes from one encoding to another, both named by WHATWG labels,
+// for example from "shift_jis" to "utf-8" or from "utf-8" to "windows-1252".
+// Characters the target encoding cannot represent are handled according to the
+// fallback option ("error", "replace", "html" or "skip") and listed in the result,
+// whose bytes are an ArrayBuffer.
+func (TextEncoding) Transcode(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	var o TranscodeOptions
+	if err := exportOptions(rt, call.Argument(3), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	out, unmappable, err := o.transcode(data, call.Argument(1).String(), call.Argument(2).String())
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(&TranscodeResult{Bytes: rt.NewArrayBuffer(out), Unmappable: unmappable})
+}
+
+// transcode converts data from the encoding named by from to the one named by to,
+// returning the converted bytes and the characters the fallback was applied to.
+func (o TranscodeOptions) transcode(data []byte, from, to string) ([]byte, []UnmappableCharacter, error) {
+	fallback, err := fallbackPolicy(o.Fallback)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		return nil, nil, err
+	}
+
+	source, err := lookupCharset(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := lookupCharset(to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text, err := source.decode(data, o.Fatal)
+	if err != nil {
+		return nil, nil, err
+	}
+	return target.encodeWithFallback(text, fallback)
+}
@@ -0,0 +1,192 @@
+package text_encoding
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTranscode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		from     string
+		to       string
+		fallback string
+		expected []byte
+	}{
+		{
+			name:     "shift_jis to utf-8",
+			input:    []byte{0x93, 0xFA, 0x96, 0x7B},
+			from:     "shift_jis",
+			to:       "utf-8",
+			expected: []byte("日本"),
+		},
+		{
+			name:     "utf-8 to windows-1252",
+			input:    []byte("café €5"),
+			from:     "utf-8",
+			to:       "windows-1252",
+			expected: []byte{'c', 'a', 'f', 0xE9, ' ', 0x80, '5'},
+		},
+		{
+			name:     "koi8-r to windows-1251",
+			input:    []byte{0xF0, 0xD2, 0xC9},
+			from:     "koi8-r",
+			to:       "windows-1251",
+			expected: []byte{0xCF, 0xF0, 0xE8},
+		},
+		{
+			name:     "replace",
+			input:    []byte("a日b"),
+			from:     "utf-8",
+			to:       "windows-1252",
+			fallback: FallbackReplace,
+			expected: []byte("a?b"),
+		},
+		{
+			name:     "html",
+			input:    []byte("a日😀b"),
+			from:     "utf-8",
+			to:       "iso-8859-1",
+			fallback: FallbackHTML,
+			expected: []byte("a&#26085;&#128512;b"),
+		},
+		{
+			name:     "skip",
+			input:    []byte("a日b"),
+			from:     "utf-8",
+			to:       "windows-1252",
+			fallback: FallbackSkip,
+			expected: []byte("ab"),
+		},
+		{
+			name:     "stateful target",
+			input:    []byte("日한b"),
+			from:     "utf-8",
+			to:       "iso-2022-jp",
+			fallback: FallbackReplace,
+			expected: []byte{0x1B, 0x24, 0x42, 0x46, 0x7C, 0x1B, 0x28, 0x42, '?', 'b'},
+		},
+		{
+			name:     "utf-16le source",
+			input:    []byte{'h', 0, 'i', 0},
+			from:     "utf-16le",
+			to:       "utf-8",
+			expected: []byte("hi"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, err := TranscodeOptions{Fallback: tt.fallback}.transcode(tt.input, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("transcode() unexpected error: %v", err)
+			}
+			if !bytes.Equal(out, tt.expected) {
+				t.Errorf("transcode() = % X, want % X", out, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranscodeUnmappable(t *testing.T) {
+	_, unmappable, err := TranscodeOptions{Fallback: FallbackSkip}.transcode([]byte("a日b😀"), "utf-8", "windows-1252")
+	if err != nil {
+		t.Fatalf("transcode() unexpected error: %v", err)
+	}
+	expected := []UnmappableCharacter{
+		{Character: "日", CodePoint: 0x65E5, Offset: 1},
+		{Character: "😀", CodePoint: 0x1F600, Offset: 5},
+	}
+	if len(unmappable) != len(expected) {
+		t.Fatalf("transcode() unmappable = %+v, want %+v", unmappable, expected)
+	}
+	for i, u := range unmappable {
+		if u != expected[i] {
+			t.Errorf("unmappable %d = %+v, want %+v", i, u, expected[i])
+		}
+	}
+
+	_, unmappable, err = TranscodeOptions{}.transcode([]byte("abc"), "utf-8", "windows-1252")
+	if err != nil {
+		t.Fatalf("transcode() unexpected error: %v", err)
+	}
+	if unmappable == nil || len(unmappable) != 0 {
+		t.Errorf("transcode() unmappable = %#v, want empty list", unmappable)
+	}
+}
+
+func TestTranscodeErrors(t *testing.T) {
+	_, _, err := TranscodeOptions{}.transcode([]byte("abc 日"), "utf-8", "windows-1252")
+	if err == nil || !strings.Contains(err.Error(), ErrUnmappableCharacter) || !strings.Contains(err.Error(), "offset 4") {
+		t.Errorf("transcode() error = %v, want unmappable character at offset 4", err)
+	}
+
+	// Invalid source bytes are replaced unless fatal is set.
+	out, _, err := TranscodeOptions{}.transcode([]byte{'a', 0xFF}, "utf-8", "utf-16le")
+	if err != nil {
+		t.Fatalf("transcode() unexpected error: %v", err)
+	}
+	if !bytes.Equal(out, []byte{'a', 0, 0xFD, 0xFF}) {
+		t.Errorf("transcode() = % X, want U+FFFD for invalid source bytes", out)
+	}
+	if _, _, err := (TranscodeOptions{Fatal: true}).transcode([]byte{'a', 0xFF}, "utf-8", "utf-16le"); err == nil {
+		t.Error("transcode() expected error for invalid source bytes in fatal mode")
+	}
+
+	// An encoded U+FFFD is not an invalid source byte.
+	out, _, err = TranscodeOptions{Fatal: true}.transcode([]byte{0x84, 0x31, 0xA4, 0x37}, "gb18030", "utf-8")
+	if err != nil {
+		t.Fatalf("transcode() unexpected error: %v", err)
+	}
+	if string(out) != "\uFFFD" {
+		t.Errorf("transcode() = % X, want U+FFFD", out)
+	}
+
+	out, _, err = TranscodeOptions{Fatal: true}.transcode([]byte{0xFF, 0xFD, 0x00, 0x41}, "utf-16be", "utf-8")
+	if err != nil {
+		t.Fatalf("transcode() unexpected error: %v", err)
+	}
+	if string(out) != "\uFFFDA" {
+		t.Errorf("transcode() = % X, want U+FFFD A", out)
+	}
+
+	if _, _, err := (TranscodeOptions{Fallback: "drop"}).transcode([]byte("a"), "utf-8", "windows-1252"); err == nil {
+		t.Error("transcode() expected error for unknown fallback")
+	}
+	if _, _, err := (TranscodeOptions{}).transcode([]byte("a"), "klingon", "utf-8"); err == nil {
+		t.Error("transcode() expected error for unknown source label")
+	}
+	if _, _, err := (TranscodeOptions{}).transcode([]byte("a"), "utf-8", "klingon"); err == nil {
+		t.Error("transcode() expected error for unknown target label")
+	}
+	if _, _, err := (TranscodeOptions{}).transcode(make([]byte, MaxInputSize+1), "utf-8", "utf-8"); err == nil {
+		t.Error("transcode() expected error for oversized input")
+	}
+}
+
+func TestTranscodeJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const r = encoding.transcode(new Uint8Array([0x61, 0xE6, 0x97, 0xA5]), 'utf-8', 'windows-1252', { fallback: 'html' });
+		const u = r.unmappable[0];
+		return [r.bytes instanceof ArrayBuffer, new Uint8Array(r.bytes).join(','), r.unmappable.length, u.character, u.codePoint, u.offset].join('|');
+	})()`).String()
+	expected := "true|97,38,35,50,54,48,56,53,59|1|日|26085|1"
+	if result != expected {
+		t.Errorf("transcode() = %q, want %q", result, expected)
+	}
+
+	throwing := []string{
+		`encoding.transcode('abc', 'utf-8', 'windows-1252')`,
+		`encoding.transcode(new Uint8Array([0x61]), 'utf-8')`,
+		`encoding.transcode(new Uint8Array([0x61]), 'utf-8', 'windows-1252', { fallback: 'drop' })`,
+	}
+	for _, script := range throwing {
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected to throw", script)
+		}
+	}
+}
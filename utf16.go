@@ -0,0 +1,230 @@
+package text_encoding
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// Error messages for UTF-16 and UTF-32 conversion
+const (
+	ErrInvalidUTF16  = "invalid UTF-16 bytes"
+	ErrInvalidUTF32  = "invalid UTF-32 bytes"
+	ErrMissingBOM    = "missing byte order mark"
+	ErrInvalidOption = "invalid option"
+)
+
+// Byte order mark handling modes for UnicodeOptions.BOM.
+const (
+	// BOMNone writes no BOM when encoding and keeps a leading BOM as U+FEFF when decoding.
+	BOMNone = "none"
+	// BOMEmit writes a BOM when encoding.
+	BOMEmit = "emit"
+	// BOMStrip removes a leading BOM in the configured byte order when decoding. It is the decoding default.
+	BOMStrip = "strip"
+	// BOMAuto detects the byte order from a leading BOM when decoding, falling back to the configured one.
+	BOMAuto = "auto"
+	// BOMRequire is like BOMAuto but fails when the input has no BOM.
+	BOMRequire = "require"
+)
+
+// UnicodeOptions configures the UTF-16 and UTF-32 encoders and decoders.
+// ByteOrder is "le" (the default) or "be". With Replace set, invalid input is
+// decoded as U+FFFD instead of failing.
+type UnicodeOptions struct {
+	ByteOrder string `js:"byteOrder"`
+	BOM       string `js:"bom"`
+	Replace   bool   `js:"replace"`
+}
+
+// byteOrder reads and appends fixed-size integers in one byte order.
+type byteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// byteOrder resolves the ByteOrder option.
+func (o UnicodeOptions) byteOrder() (byteOrder, error) {
+	switch strings.ToLower(o.ByteOrder) {
+	case "", "le":
+		return binary.LittleEndian, nil
+	case "be":
+		return binary.BigEndian, nil
+	default:
+		return nil, fmt.Errorf("%s: byteOrder must be \"le\" or \"be\", got %q", ErrInvalidOption, o.ByteOrder)
+	}
+}
+
+// encodeBOM reports whether the BOM option asks for a BOM when encoding.
+func (o UnicodeOptions) encodeBOM() (bool, error) {
+	switch o.BOM {
+	case "", BOMNone:
+		return false, nil
+	case BOMEmit:
+		return true, nil
+	default:
+		return false, fmt.Errorf("%s: bom must be %q or %q when encoding, got %q", ErrInvalidOption, BOMNone, BOMEmit, o.BOM)
+	}
+}
+
+// decodeBOM applies the BOM option to data, returning the payload after any BOM
+// and the byte order to decode it with. le and be are the BOM bytes for each order.
+func (o UnicodeOptions) decodeBOM(data, le, be []byte) ([]byte, byteOrder, error) {
+	order, err := o.byteOrder()
+	if err != nil {
+		return nil, nil, err
+	}
+	own := le
+	if strings.EqualFold(o.ByteOrder, "be") {
+		own = be
+	}
+
+	switch o.BOM {
+	case "", BOMStrip:
+		return bytesTrimPrefix(data, own), order, nil
+	case BOMNone:
+		return data, order, nil
+	case BOMAuto, BOMRequire:
+		switch {
+		case hasBytePrefix(data, le):
+			return data[len(le):], binary.LittleEndian, nil
+		case hasBytePrefix(data, be):
+			return data[len(be):], binary.BigEndian, nil
+		case o.BOM == BOMRequire:
+			return nil, nil, errors.New(ErrMissingBOM)
+		}
+		return data, order, nil
+	default:
+		return nil, nil, fmt.Errorf("%s: bom must be one of %q, %q, %q or %q when decoding, got %q",
+			ErrInvalidOption, BOMStrip, BOMNone, BOMAuto, BOMRequire, o.BOM)
+	}
+}
+
+// hasBytePrefix reports whether data begins with prefix.
+func hasBytePrefix(data, prefix []byte) bool {
+	return len(data) >= len(prefix) && string(data[:len(prefix)]) == string(prefix)
+}
+
+// bytesTrimPrefix returns data without a leading prefix.
+func bytesTrimPrefix(data, prefix []byte) []byte {
+	if hasBytePrefix(data, prefix) {
+		return data[len(prefix):]
+	}
+	return data
+}
+
+var (
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// EncodeUTF16 converts a string to UTF-16 bytes in the requested byte order,
+// optionally preceded by a byte order mark, and returns them as an ArrayBuffer.
+func (TextEncoding) EncodeUTF16(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	text, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	var o UnicodeOptions
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encodeUTF16(text)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(encoded))
+}
+
+// encodeUTF16 converts text to UTF-16 bytes as configured.
+func (o UnicodeOptions) encodeUTF16(text string) ([]byte, error) {
+	order, err := o.byteOrder()
+	if err != nil {
+		return nil, err
+	}
+	withBOM, err := o.encodeBOM()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateInputSize(len(text)); err != nil {
+		return nil, err
+	}
+	if err := validateUTF8String(text); err != nil {
+		return nil, err
+	}
+
+	units := utf16.Encode([]rune(text))
+	out := make([]byte, 0, 2*len(units)+2)
+	if withBOM {
+		out = order.AppendUint16(out, 0xFEFF)
+	}
+	for _, u := range units {
+		out = order.AppendUint16(out, u)
+	}
+	return out, nil
+}
+
+// DecodeUTF16 converts UTF-16 bytes to a string, handling a byte order mark as
+// configured. Unpaired surrogates and a trailing odd byte are errors unless the
+// replace option is set.
+func (TextEncoding) DecodeUTF16(data []byte, opts ...UnicodeOptions) (string, error) {
+	var o UnicodeOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		return "", err
+	}
+	payload, order, err := o.decodeBOM(data, utf16LEBOM, utf16BEBOM)
+	if err != nil {
+		return "", err
+	}
+	text, _, err := decodeUTF16Chunk(payload, order, o.Replace, true, len(data)-len(payload))
+	return text, err
+}
+
+// decodeUTF16Chunk decodes UTF-16 data in the given byte order. Unpaired surrogates and
+// a trailing odd byte are replaced with U+FFFD when replace is set, and are otherwise
+// errors reported at their offset in data plus base. Unless flush is set, a trailing odd
+// byte or high surrogate is returned unconsumed.
+func decodeUTF16Chunk(data []byte, order byteOrder, replace, flush bool, base int) (string, []byte, error) {
+	var sb strings.Builder
+	sb.Grow(len(data) * 3 / 2)
+	i := 0
+	for ; i+1 < len(data); i += 2 {
+		u := order.Uint16(data[i:])
+		switch {
+		case !utf16.IsSurrogate(rune(u)):
+			sb.WriteRune(rune(u))
+			continue
+		case u < 0xDC00 && i+3 < len(data):
+			if low := order.Uint16(data[i+2:]); low >= 0xDC00 && low <= 0xDFFF {
+				sb.WriteRune(utf16.DecodeRune(rune(u), rune(low)))
+				i += 2
+				continue
+			}
+		case u < 0xDC00 && !flush:
+			return sb.String(), data[i:], nil
+		}
+		if !replace {
+			return "", nil, fmt.Errorf("%s: unpaired surrogate 0x%04X at offset %d", ErrInvalidUTF16, u, base+i)
+		}
+		sb.WriteRune(utf8.RuneError)
+	}
+	if i < len(data) {
+		if !flush {
+			return sb.String(), data[i:], nil
+		}
+		if !replace {
+			return "", nil, fmt.Errorf("%s: odd trailing byte at offset %d", ErrInvalidUTF16, base+i)
+		}
+		sb.WriteRune(utf8.RuneError)
+	}
+	return sb.String(), nil, nil
+}
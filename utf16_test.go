@@ -0,0 +1,292 @@
+package text_encoding
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncodeUTF16(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		opts     UnicodeOptions
+		expected []byte
+	}{
+		{
+			name:     "empty string",
+			text:     "",
+			expected: []byte{},
+		},
+		{
+			name:     "little endian by default",
+			text:     "hi",
+			expected: []byte{'h', 0, 'i', 0},
+		},
+		{
+			name:     "big endian",
+			text:     "hi",
+			opts:     UnicodeOptions{ByteOrder: "be"},
+			expected: []byte{0, 'h', 0, 'i'},
+		},
+		{
+			name:     "surrogate pair",
+			text:     "🌍",
+			expected: []byte{0x3C, 0xD8, 0x0D, 0xDF},
+		},
+		{
+			name:     "little endian BOM",
+			text:     "é",
+			opts:     UnicodeOptions{BOM: BOMEmit},
+			expected: []byte{0xFF, 0xFE, 0xE9, 0x00},
+		},
+		{
+			name:     "big endian BOM",
+			text:     "é",
+			opts:     UnicodeOptions{ByteOrder: "BE", BOM: BOMEmit},
+			expected: []byte{0xFE, 0xFF, 0x00, 0xE9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.opts.encodeUTF16(tt.text)
+			if err != nil {
+				t.Fatalf("encodeUTF16() unexpected error: %v", err)
+			}
+			if !bytes.Equal(result, tt.expected) {
+				t.Errorf("encodeUTF16() = % X, want % X", result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := (UnicodeOptions{ByteOrder: "middle"}).encodeUTF16("hi"); err == nil {
+		t.Error("encodeUTF16() expected error for invalid byte order")
+	}
+	if _, err := (UnicodeOptions{BOM: BOMRequire}).encodeUTF16("hi"); err == nil {
+		t.Error("encodeUTF16() expected error for decode-only BOM mode")
+	}
+	if _, err := (UnicodeOptions{}).encodeUTF16(string([]byte{0xFF})); err == nil {
+		t.Error("encodeUTF16() expected error for invalid UTF-8 input")
+	}
+}
+
+func TestDecodeUTF16(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name        string
+		input       []byte
+		opts        UnicodeOptions
+		expected    string
+		expectError string
+	}{
+		{
+			name:     "little endian",
+			input:    []byte{'h', 0, 'i', 0},
+			expected: "hi",
+		},
+		{
+			name:     "big endian",
+			input:    []byte{0, 'h', 0, 'i'},
+			opts:     UnicodeOptions{ByteOrder: "be"},
+			expected: "hi",
+		},
+		{
+			name:     "surrogate pair",
+			input:    []byte{0x3C, 0xD8, 0x0D, 0xDF},
+			expected: "🌍",
+		},
+		{
+			name:     "strips BOM by default",
+			input:    []byte{0xFF, 0xFE, 'h', 0},
+			expected: "h",
+		},
+		{
+			name:     "keeps BOM with none",
+			input:    []byte{0xFF, 0xFE, 'h', 0},
+			opts:     UnicodeOptions{BOM: BOMNone},
+			expected: "\uFEFFh",
+		},
+		{
+			name:     "auto detects big endian",
+			input:    []byte{0xFE, 0xFF, 0, 'h'},
+			opts:     UnicodeOptions{BOM: BOMAuto},
+			expected: "h",
+		},
+		{
+			name:     "auto falls back to byte order",
+			input:    []byte{0, 'h'},
+			opts:     UnicodeOptions{ByteOrder: "be", BOM: BOMAuto},
+			expected: "h",
+		},
+		{
+			name:     "require accepts BOM",
+			input:    []byte{0xFF, 0xFE, 'h', 0},
+			opts:     UnicodeOptions{ByteOrder: "be", BOM: BOMRequire},
+			expected: "h",
+		},
+		{
+			name:        "require rejects missing BOM",
+			input:       []byte{'h', 0},
+			opts:        UnicodeOptions{BOM: BOMRequire},
+			expectError: ErrMissingBOM,
+		},
+		{
+			name:        "unpaired high surrogate",
+			input:       []byte{'a', 0, 0x3C, 0xD8, 'b', 0},
+			expectError: "unpaired surrogate 0xD83C at offset 2",
+		},
+		{
+			name:        "unpaired low surrogate",
+			input:       []byte{0x0D, 0xDF},
+			expectError: ErrInvalidUTF16,
+		},
+		{
+			name:        "high surrogate at end",
+			input:       []byte{0x3C, 0xD8},
+			expectError: ErrInvalidUTF16,
+		},
+		{
+			name:        "odd length",
+			input:       []byte{'h', 0, 'i'},
+			expectError: "odd trailing byte at offset 2",
+		},
+		{
+			name:     "replace unpaired surrogate",
+			input:    []byte{'a', 0, 0x3C, 0xD8, 'b', 0},
+			opts:     UnicodeOptions{Replace: true},
+			expected: "a\uFFFDb",
+		},
+		{
+			name:     "replace odd byte",
+			input:    []byte{'h', 0, 'i'},
+			opts:     UnicodeOptions{Replace: true},
+			expected: "h\uFFFD",
+		},
+		{
+			name:        "invalid bom option",
+			input:       []byte{'h', 0},
+			opts:        UnicodeOptions{BOM: BOMEmit},
+			expectError: ErrInvalidOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.DecodeUTF16(tt.input, tt.opts)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("DecodeUTF16() error = %v, want %q", err, tt.expectError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeUTF16() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("DecodeUTF16() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUTF16RoundTrip(t *testing.T) {
+	te := &TextEncoding{}
+	text := "Hello 🌍 你好 café 𝄞 👨‍👩‍👧‍👦"
+
+	for _, order := range []string{"le", "be"} {
+		opts := UnicodeOptions{ByteOrder: order, BOM: BOMEmit}
+		encoded, err := opts.encodeUTF16(text)
+		if err != nil {
+			t.Fatalf("encodeUTF16() error: %v", err)
+		}
+		decoded, err := te.DecodeUTF16(encoded, UnicodeOptions{BOM: BOMRequire})
+		if err != nil {
+			t.Fatalf("DecodeUTF16() error: %v", err)
+		}
+		if decoded != text {
+			t.Errorf("%s round trip = %q, want %q", order, decoded, text)
+		}
+	}
+}
+
+func TestTextDecoderUTF16(t *testing.T) {
+	decoder, err := NewTextDecoder("utf-16le", TextDecoderOptions{})
+	if err != nil {
+		t.Fatalf("NewTextDecoder() error: %v", err)
+	}
+	input := []byte{0xFF, 0xFE, 'h', 0, 0x3C, 0xD8, 0x0D, 0xDF}
+	var result string
+	for i := range input {
+		chunk, err := decoder.decodeBytes(input[i:i+1], true)
+		if err != nil {
+			t.Fatalf("decode() chunk %d error: %v", i, err)
+		}
+		result += chunk
+	}
+	tail, _ := decoder.decodeBytes(nil, false)
+	result += tail
+	if result != "h🌍" {
+		t.Errorf("streamed decode = %q, want %q", result, "h🌍")
+	}
+
+	decoder, _ = NewTextDecoder("utf-16be", TextDecoderOptions{Fatal: true})
+	if decoder.Encoding != "utf-16be" {
+		t.Errorf("Encoding = %q, want %q", decoder.Encoding, "utf-16be")
+	}
+	if _, err := decoder.decodeBytes([]byte{0xD8, 0x3C}, false); err == nil {
+		t.Error("decode() expected error for unpaired surrogate in fatal mode")
+	}
+
+	// U+FFFD is a character like any other, which fatal mode must not reject.
+	result, err = decoder.decodeBytes([]byte{0xFF, 0xFD, 0x00, 0x41}, true)
+	if err != nil || result != "\uFFFDA" {
+		t.Errorf("decode() = %q, %v, want %q", result, err, "\uFFFDA")
+	}
+	// A high surrogate is kept for the next chunk, and fails at the end of the stream.
+	if result, err = decoder.decodeBytes([]byte{0xD8, 0x3C}, true); err != nil || result != "" {
+		t.Errorf("decode() = %q, %v, want the surrogate kept", result, err)
+	}
+	if _, err := decoder.decodeBytes(nil, false); err == nil || !strings.Contains(err.Error(), ErrInvalidUTF16) {
+		t.Errorf("decode() error = %v, want %q", err, ErrInvalidUTF16)
+	}
+
+	decoder, _ = NewTextDecoder("utf-16le", TextDecoderOptions{})
+	if result, _ = decoder.decodeBytes([]byte{0x3D, 0xD8, 0x41, 0x00, 0x42}, false); result != "\uFFFDA\uFFFD" {
+		t.Errorf("decode() = %q, want %q", result, "\uFFFDA\uFFFD")
+	}
+}
+
+func TestUnicodeJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const utf16 = encoding.encodeUTF16('hi', { byteOrder: 'be', bom: 'emit' });
+		const utf32 = encoding.encodeUTF32('a🌍');
+		return [
+			utf16 instanceof ArrayBuffer,
+			new Uint8Array(utf16).join(','),
+			encoding.decodeUTF16(utf16, { bom: 'require' }),
+			utf32 instanceof ArrayBuffer,
+			utf32.byteLength,
+			encoding.decodeUTF32(utf32),
+		].join('|');
+	})()`).String()
+	expected := "true|254,255,0,104,0,105|hi|true|8|a🌍"
+	if result != expected {
+		t.Errorf("unicode = %q, want %q", result, expected)
+	}
+
+	throwing := []string{
+		`encoding.encodeUTF16()`,
+		`encoding.encodeUTF16('hi', { byteOrder: 'middle' })`,
+		`encoding.encodeUTF32(null)`,
+		`encoding.encodeUTF32('hi', { bom: 'require' })`,
+	}
+	for _, script := range throwing {
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected to throw", script)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package text_encoding
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+var (
+	utf32LEBOM = []byte{0xFF, 0xFE, 0x00, 0x00}
+	utf32BEBOM = []byte{0x00, 0x00, 0xFE, 0xFF}
+)
+
+// EncodeUTF32 converts a string to UTF-32 bytes in the requested byte order,
+// optionally preceded by a byte order mark, and returns them as an ArrayBuffer.
+func (TextEncoding) EncodeUTF32(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	text, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	var o UnicodeOptions
+	if err := exportOptions(rt, call.Argument(1), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := o.encodeUTF32(text)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(rt.NewArrayBuffer(encoded))
+}
+
+// encodeUTF32 converts text to UTF-32 bytes as configured.
+func (o UnicodeOptions) encodeUTF32(text string) ([]byte, error) {
+	order, err := o.byteOrder()
+	if err != nil {
+		return nil, err
+	}
+	withBOM, err := o.encodeBOM()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateInputSize(len(text)); err != nil {
+		return nil, err
+	}
+	if err := validateUTF8String(text); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 4*utf8.RuneCountInString(text)+4)
+	if withBOM {
+		out = order.AppendUint32(out, 0xFEFF)
+	}
+	for _, r := range text {
+		out = order.AppendUint32(out, uint32(r))
+	}
+	return out, nil
+}
+
+// DecodeUTF32 converts UTF-32 bytes to a string, handling a byte order mark as
+// configured. Surrogates, values above U+10FFFF and a length that is not a
+// multiple of four are errors unless the replace option is set.
+func (TextEncoding) DecodeUTF32(data []byte, opts ...UnicodeOptions) (string, error) {
+	var o UnicodeOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		return "", err
+	}
+	payload, order, err := o.decodeBOM(data, utf32LEBOM, utf32BEBOM)
+	if err != nil {
+		return "", err
+	}
+	offset := len(data) - len(payload)
+
+	var sb strings.Builder
+	sb.Grow(len(payload))
+	i := 0
+	for ; i+3 < len(payload); i += 4 {
+		v := order.Uint32(payload[i:])
+		if v <= utf8.MaxRune && (v < 0xD800 || v > 0xDFFF) {
+			sb.WriteRune(rune(v))
+			continue
+		}
+		if !o.Replace {
+			return "", fmt.Errorf("%s: invalid code point 0x%X at offset %d", ErrInvalidUTF32, v, offset+i)
+		}
+		sb.WriteRune(utf8.RuneError)
+	}
+	if i < len(payload) {
+		if !o.Replace {
+			return "", fmt.Errorf("%s: %d trailing bytes at offset %d", ErrInvalidUTF32, len(payload)-i, offset+i)
+		}
+		sb.WriteRune(utf8.RuneError)
+	}
+	return sb.String(), nil
+}
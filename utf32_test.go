@@ -0,0 +1,120 @@
+package text_encoding
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncodeUTF32(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		opts     UnicodeOptions
+		expected []byte
+	}{
+		{
+			name:     "little endian by default",
+			text:     "a🌍",
+			expected: []byte{'a', 0, 0, 0, 0x0D, 0xF3, 0x01, 0x00},
+		},
+		{
+			name:     "big endian",
+			text:     "a🌍",
+			opts:     UnicodeOptions{ByteOrder: "be"},
+			expected: []byte{0, 0, 0, 'a', 0x00, 0x01, 0xF3, 0x0D},
+		},
+		{
+			name:     "BOM",
+			text:     "a",
+			opts:     UnicodeOptions{ByteOrder: "be", BOM: BOMEmit},
+			expected: []byte{0, 0, 0xFE, 0xFF, 0, 0, 0, 'a'},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.opts.encodeUTF32(tt.text)
+			if err != nil {
+				t.Fatalf("encodeUTF32() unexpected error: %v", err)
+			}
+			if !bytes.Equal(result, tt.expected) {
+				t.Errorf("encodeUTF32() = % X, want % X", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeUTF32(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name        string
+		input       []byte
+		opts        UnicodeOptions
+		expected    string
+		expectError string
+	}{
+		{
+			name:     "little endian",
+			input:    []byte{'a', 0, 0, 0, 0x0D, 0xF3, 0x01, 0x00},
+			expected: "a🌍",
+		},
+		{
+			name:     "auto detects big endian",
+			input:    []byte{0, 0, 0xFE, 0xFF, 0, 0, 0, 'a'},
+			opts:     UnicodeOptions{BOM: BOMAuto},
+			expected: "a",
+		},
+		{
+			name:     "strips little endian BOM",
+			input:    []byte{0xFF, 0xFE, 0, 0, 'a', 0, 0, 0},
+			expected: "a",
+		},
+		{
+			name:        "require rejects missing BOM",
+			input:       []byte{'a', 0, 0, 0},
+			opts:        UnicodeOptions{BOM: BOMRequire},
+			expectError: ErrMissingBOM,
+		},
+		{
+			name:        "surrogate code point",
+			input:       []byte{0x00, 0xD8, 0, 0},
+			expectError: "invalid code point 0xD800 at offset 0",
+		},
+		{
+			name:        "above U+10FFFF",
+			input:       []byte{0, 0, 0x11, 0},
+			expectError: ErrInvalidUTF32,
+		},
+		{
+			name:        "truncated",
+			input:       []byte{'a', 0, 0, 0, 'b', 0},
+			expectError: "2 trailing bytes at offset 4",
+		},
+		{
+			name:     "replace invalid",
+			input:    []byte{'a', 0, 0, 0, 0, 0, 0x11, 0, 'b', 0},
+			opts:     UnicodeOptions{Replace: true},
+			expected: "a\uFFFD\uFFFD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.DecodeUTF32(tt.input, tt.opts)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("DecodeUTF32() error = %v, want %q", err, tt.expectError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeUTF32() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("DecodeUTF32() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package text_encoding
+
+import (
+	"fmt"
+)
+
+// Classifications of invalid UTF-8 sequences reported by DiagnoseUTF8.
+const (
+	InvalidUTF8Overlong               = "overlong"
+	InvalidUTF8Surrogate              = "surrogate"
+	InvalidUTF8OutOfRange             = "out-of-range"
+	InvalidUTF8Truncated              = "truncated"
+	InvalidUTF8UnexpectedContinuation = "unexpected-continuation"
+	InvalidUTF8Byte                   = "invalid-byte"
+)
+
+// UTF8Finding describes a single invalid sequence found by DiagnoseUTF8.
+type UTF8Finding struct {
+	Offset int    `js:"offset"`
+	Length int    `js:"length"`
+	Bytes  string `js:"bytes"`
+	Kind   string `js:"kind"`
+}
+
+// UTF8Diagnosis is the result of DiagnoseUTF8.
+// Limited is set when the findings were cut off by DiagnoseOptions.MaxFindings.
+type UTF8Diagnosis struct {
+	Valid    bool          `js:"valid"`
+	Findings []UTF8Finding `js:"findings"`
+	Limited  bool          `js:"limited"`
+}
+
+// DiagnoseOptions configures DiagnoseUTF8.
+// A MaxFindings of zero reports every invalid sequence.
+type DiagnoseOptions struct {
+	MaxFindings int `js:"maxFindings"`
+}
+
+// DiagnoseUTF8 reports every invalid UTF-8 sequence in data with its byte offset,
+// length, the offending bytes in hex and a classification of the problem.
+func (TextEncoding) DiagnoseUTF8(data []byte, opts ...DiagnoseOptions) (*UTF8Diagnosis, error) {
+	if err := validateInputSize(len(data)); err != nil {
+		return nil, err
+	}
+	var maxFindings int
+	if len(opts) > 0 {
+		maxFindings = opts[0].MaxFindings
+	}
+	if maxFindings < 0 {
+		return nil, fmt.Errorf("maxFindings must not be negative, got %d", maxFindings)
+	}
+
+	diagnosis := &UTF8Diagnosis{Valid: true, Findings: []UTF8Finding{}}
+	for i := 0; i < len(data); {
+		n, valid, _ := scanUTF8(data[i:])
+		if valid {
+			i += n
+			continue
+		}
+		diagnosis.Valid = false
+		if maxFindings > 0 && len(diagnosis.Findings) == maxFindings {
+			diagnosis.Limited = true
+			break
+		}
+		kind, size := classifyInvalidUTF8(data[i:], n)
+		diagnosis.Findings = append(diagnosis.Findings, UTF8Finding{
+			Offset: i,
+			Length: size,
+			Bytes:  fmt.Sprintf("% X", data[i:i+size]),
+			Kind:   kind,
+		})
+		i += size
+	}
+	return diagnosis, nil
+}
+
+// classifyInvalidUTF8 explains why the sequence at seq[0] is invalid, given the
+// length n of its maximal subpart. Overlong, surrogate and out-of-range sequences
+// are reported together with the continuation bytes that belong to them.
+func classifyInvalidUTF8(seq []byte, n int) (kind string, size int) {
+	lead := seq[0]
+	switch {
+	case lead >= 0x80 && lead <= 0xBF:
+		return InvalidUTF8UnexpectedContinuation, 1
+	case lead == 0xC0 || lead == 0xC1:
+		return InvalidUTF8Overlong, withContinuations(seq, 1)
+	case lead >= 0xF8:
+		return InvalidUTF8Byte, 1
+	case lead >= 0xF5:
+		return InvalidUTF8OutOfRange, withContinuations(seq, 3)
+	case n == 1 && len(seq) > 1 && isContinuation(seq[1]):
+		// The second byte is a continuation byte outside the range the lead byte allows.
+		switch lead {
+		case 0xE0:
+			return InvalidUTF8Overlong, withContinuations(seq, 2)
+		case 0xED:
+			return InvalidUTF8Surrogate, withContinuations(seq, 2)
+		case 0xF0:
+			return InvalidUTF8Overlong, withContinuations(seq, 3)
+		case 0xF4:
+			return InvalidUTF8OutOfRange, withContinuations(seq, 3)
+		}
+	}
+	return InvalidUTF8Truncated, n
+}
+
+// withContinuations returns the length of the lead byte plus up to need continuation bytes.
+func withContinuations(seq []byte, need int) int {
+	size := 1
+	for size <= need && size < len(seq) && isContinuation(seq[size]) {
+		size++
+	}
+	return size
+}
+
+// isContinuation reports whether b is a UTF-8 continuation byte.
+func isContinuation(b byte) bool {
+	return b >= 0x80 && b <= 0xBF
+}
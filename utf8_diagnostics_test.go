@@ -0,0 +1,163 @@
+package text_encoding
+
+import (
+	"testing"
+)
+
+func TestDiagnoseUTF8(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    []byte
+		expected []UTF8Finding
+	}{
+		{
+			name:     "empty bytes",
+			input:    []byte{},
+			expected: []UTF8Finding{},
+		},
+		{
+			name:     "valid unicode",
+			input:    []byte("Hello 🌍 你好"),
+			expected: []UTF8Finding{},
+		},
+		{
+			name:  "overlong two-byte",
+			input: []byte{'a', 0xC0, 0xAF, 'b'},
+			expected: []UTF8Finding{
+				{Offset: 1, Length: 2, Bytes: "C0 AF", Kind: InvalidUTF8Overlong},
+			},
+		},
+		{
+			name:  "overlong three-byte",
+			input: []byte{0xE0, 0x80, 0xAF},
+			expected: []UTF8Finding{
+				{Offset: 0, Length: 3, Bytes: "E0 80 AF", Kind: InvalidUTF8Overlong},
+			},
+		},
+		{
+			name:  "overlong four-byte",
+			input: []byte{0xF0, 0x80, 0x80, 0xAF},
+			expected: []UTF8Finding{
+				{Offset: 0, Length: 4, Bytes: "F0 80 80 AF", Kind: InvalidUTF8Overlong},
+			},
+		},
+		{
+			name:  "surrogate",
+			input: []byte{'x', 0xED, 0xA0, 0x80},
+			expected: []UTF8Finding{
+				{Offset: 1, Length: 3, Bytes: "ED A0 80", Kind: InvalidUTF8Surrogate},
+			},
+		},
+		{
+			name:  "above U+10FFFF",
+			input: []byte{0xF4, 0x90, 0x80, 0x80},
+			expected: []UTF8Finding{
+				{Offset: 0, Length: 4, Bytes: "F4 90 80 80", Kind: InvalidUTF8OutOfRange},
+			},
+		},
+		{
+			name:  "out-of-range lead byte",
+			input: []byte{0xF5, 0x80},
+			expected: []UTF8Finding{
+				{Offset: 0, Length: 2, Bytes: "F5 80", Kind: InvalidUTF8OutOfRange},
+			},
+		},
+		{
+			name:  "truncated at end",
+			input: []byte{'o', 'k', 0xF0, 0x9F, 0x8C},
+			expected: []UTF8Finding{
+				{Offset: 2, Length: 3, Bytes: "F0 9F 8C", Kind: InvalidUTF8Truncated},
+			},
+		},
+		{
+			name:  "truncated by ascii",
+			input: []byte{0xE4, 0xBD, 'a'},
+			expected: []UTF8Finding{
+				{Offset: 0, Length: 2, Bytes: "E4 BD", Kind: InvalidUTF8Truncated},
+			},
+		},
+		{
+			name:  "unexpected continuation",
+			input: []byte{0x80, 0xBF},
+			expected: []UTF8Finding{
+				{Offset: 0, Length: 1, Bytes: "80", Kind: InvalidUTF8UnexpectedContinuation},
+				{Offset: 1, Length: 1, Bytes: "BF", Kind: InvalidUTF8UnexpectedContinuation},
+			},
+		},
+		{
+			name:  "invalid bytes",
+			input: []byte{0xFF, 0xFE},
+			expected: []UTF8Finding{
+				{Offset: 0, Length: 1, Bytes: "FF", Kind: InvalidUTF8Byte},
+				{Offset: 1, Length: 1, Bytes: "FE", Kind: InvalidUTF8Byte},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.DiagnoseUTF8(tt.input)
+			if err != nil {
+				t.Fatalf("DiagnoseUTF8() unexpected error: %v", err)
+			}
+			if result.Valid != (len(tt.expected) == 0) {
+				t.Errorf("DiagnoseUTF8() valid = %v, want %v", result.Valid, len(tt.expected) == 0)
+			}
+			if result.Limited {
+				t.Error("DiagnoseUTF8() should not be limited without maxFindings")
+			}
+			if len(result.Findings) != len(tt.expected) {
+				t.Fatalf("DiagnoseUTF8() findings = %+v, want %+v", result.Findings, tt.expected)
+			}
+			for i, finding := range result.Findings {
+				if finding != tt.expected[i] {
+					t.Errorf("finding %d = %+v, want %+v", i, finding, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiagnoseUTF8MaxFindings(t *testing.T) {
+	te := &TextEncoding{}
+	input := []byte{0xFF, 'a', 0xFF, 'b', 0xFF}
+
+	result, err := te.DiagnoseUTF8(input, DiagnoseOptions{MaxFindings: 2})
+	if err != nil {
+		t.Fatalf("DiagnoseUTF8() unexpected error: %v", err)
+	}
+	if result.Valid || !result.Limited || len(result.Findings) != 2 {
+		t.Errorf("DiagnoseUTF8() = %+v, want 2 findings and limited", result)
+	}
+
+	result, err = te.DiagnoseUTF8(input, DiagnoseOptions{MaxFindings: 3})
+	if err != nil {
+		t.Fatalf("DiagnoseUTF8() unexpected error: %v", err)
+	}
+	if result.Limited || len(result.Findings) != 3 {
+		t.Errorf("DiagnoseUTF8() = %+v, want 3 findings and not limited", result)
+	}
+
+	if _, err := te.DiagnoseUTF8(input, DiagnoseOptions{MaxFindings: -1}); err == nil {
+		t.Error("DiagnoseUTF8() expected error for negative maxFindings")
+	}
+	if _, err := te.DiagnoseUTF8(make([]byte, MaxInputSize+1)); err == nil {
+		t.Error("DiagnoseUTF8() expected error for oversized input")
+	}
+}
+
+func TestDiagnoseUTF8JS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const d = encoding.diagnoseUTF8(new Uint8Array([0x61, 0xED, 0xA0, 0x80, 0xFF]), { maxFindings: 1 });
+		const f = d.findings[0];
+		return [d.valid, d.limited, d.findings.length, f.offset, f.length, f.bytes, f.kind].join('|');
+	})()`).String()
+	expected := "false|true|1|1|3|ED A0 80|surrogate"
+	if result != expected {
+		t.Errorf("diagnoseUTF8() = %q, want %q", result, expected)
+	}
+}
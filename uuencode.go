@@ -0,0 +1,273 @@
+package text_encoding
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/common"
+)
+
+// Error messages for uuencode and xxencode
+const (
+	ErrInvalidUU = "failed to decode uuencode"
+	ErrInvalidXX = "failed to decode xxencode"
+)
+
+// uuLineSize is the number of bytes encoded on each full line.
+const uuLineSize = 45
+
+// defaultUUMode is the permission mode written when the options leave it empty.
+const defaultUUMode = "644"
+
+var (
+	// uuencodeCodec writes the value 0 as '`' like GNU uuencode, and also reads it as ' '.
+	uuencodeCodec = newUUCodec(ErrInvalidUU, "`!\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_", ' ')
+	xxencodeCodec = newUUCodec(ErrInvalidXX, "+-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz", 0)
+)
+
+// UUOptions configures the uuencode and xxencode encoders.
+// Mode is the octal permission mode written in the "begin" header, "644" by default.
+type UUOptions struct {
+	Mode string `js:"mode"`
+}
+
+// UUResult is the result of DecodeUU and DecodeXX.
+type UUResult struct {
+	Mode     string            `js:"mode"`
+	Filename string            `js:"filename"`
+	Data     sobek.ArrayBuffer `js:"data"`
+}
+
+// uuBlock is a decoded uuencoded or xxencoded block.
+type uuBlock struct {
+	mode     string
+	filename string
+	data     []byte
+}
+
+// uuCodec holds the 64 characters of a uuencode-style alphabet and their values.
+type uuCodec struct {
+	errMsg    string
+	digits    string
+	values    [256]int8
+	zeroAlias byte
+}
+
+// newUUCodec indexes digits. A non-zero zeroAlias is also read as the value 0, for
+// encoders that write it as a space.
+func newUUCodec(errMsg, digits string, zeroAlias byte) *uuCodec {
+	c := &uuCodec{errMsg: errMsg, digits: digits, zeroAlias: zeroAlias}
+	for i := range c.values {
+		c.values[i] = -1
+	}
+	for i := 0; i < len(digits); i++ {
+		c.values[digits[i]] = int8(i)
+	}
+	if zeroAlias != 0 {
+		c.values[zeroAlias] = 0
+	}
+	return c
+}
+
+// validateUUMode checks that mode is three or four octal digits.
+func validateUUMode(mode string) error {
+	if len(mode) < 3 || len(mode) > 4 || strings.Trim(mode, "01234567") != "" {
+		return fmt.Errorf("mode must be 3 or 4 octal digits, got %q", mode)
+	}
+	return nil
+}
+
+// encode writes a full block: the "begin" header, lines of at most 45 bytes each
+// prefixed with their length, a zero-length line and the "end" trailer.
+func (c *uuCodec) encode(data []byte, filename string, o UUOptions) (string, error) {
+	mode := defaultUUMode
+	if o.Mode != "" {
+		mode = o.Mode
+	}
+	if err := validateUUMode(mode); err != nil {
+		return "", fmt.Errorf("%s: %w", ErrInvalidOption, err)
+	}
+	if filename == "" || strings.ContainsAny(filename, "\r\n") {
+		return "", fmt.Errorf("%s: filename must be a non-empty single line, got %q", ErrInvalidOption, filename)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(data)/3*4 + len(data)/uuLineSize*2 + len(filename) + 32)
+	sb.WriteString("begin " + mode + " " + filename + "\n")
+	for len(data) > 0 {
+		n := min(len(data), uuLineSize)
+		sb.WriteByte(c.digits[n])
+		for i := 0; i < n; i += 3 {
+			var group [3]byte
+			copy(group[:], data[i:n])
+			sb.WriteByte(c.digits[group[0]>>2])
+			sb.WriteByte(c.digits[(group[0]&0x03)<<4|group[1]>>4])
+			sb.WriteByte(c.digits[(group[1]&0x0F)<<2|group[2]>>6])
+			sb.WriteByte(c.digits[group[2]&0x3F])
+		}
+		sb.WriteByte('\n')
+		data = data[n:]
+	}
+	sb.WriteByte(c.digits[0])
+	sb.WriteString("\nend\n")
+	return sb.String(), nil
+}
+
+// decode parses the first block in encoded. Text before the "begin" header is ignored,
+// lines may end in CRLF and the zero-length line before "end" may be missing. Errors
+// report the line number, counting from 1.
+func (c *uuCodec) decode(encoded string) (*uuBlock, error) {
+	lines := strings.Split(encoded, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "begin ") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("%s: missing \"begin\" line", c.errMsg)
+	}
+	mode, filename, ok := strings.Cut(strings.TrimPrefix(lines[start], "begin "), " ")
+	if !ok || filename == "" {
+		return nil, fmt.Errorf("%s: malformed header on line %d", c.errMsg, start+1)
+	}
+	if err := validateUUMode(mode); err != nil {
+		return nil, fmt.Errorf("%s: %w on line %d", c.errMsg, err, start+1)
+	}
+
+	block := &uuBlock{mode: mode, filename: filename, data: []byte{}}
+	ended := false
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		if line == "end" {
+			return block, nil
+		}
+		if line == "" {
+			if ended {
+				continue
+			}
+			// A zero-length line written as a single space loses it to whitespace stripping.
+			if c.zeroAlias == ' ' {
+				ended = true
+				continue
+			}
+			return nil, fmt.Errorf("%s: empty line %d", c.errMsg, i+1)
+		}
+		if ended {
+			return nil, fmt.Errorf("%s: expected \"end\" on line %d", c.errMsg, i+1)
+		}
+		n := int(c.values[line[0]])
+		if n < 0 || n > uuLineSize {
+			return nil, fmt.Errorf("%s: invalid length character %q on line %d", c.errMsg, line[0], i+1)
+		}
+		if n == 0 {
+			ended = true
+			continue
+		}
+		decoded, err := c.decodeLine(line[1:], n)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w on line %d", c.errMsg, err, i+1)
+		}
+		block.data = append(block.data, decoded...)
+	}
+	return nil, fmt.Errorf("%s: missing \"end\" line", c.errMsg)
+}
+
+// decodeLine decodes the n bytes held by the characters of a line after its length
+// character. Characters after the last group are ignored. Missing characters are read as
+// zero when the codec has a space alias, since some transports strip trailing spaces.
+func (c *uuCodec) decodeLine(chars string, n int) ([]byte, error) {
+	need := (n + 2) / 3 * 4
+	if len(chars) < need {
+		if c.zeroAlias != ' ' {
+			return nil, fmt.Errorf("line holds %d characters, need %d", len(chars), need)
+		}
+		chars += strings.Repeat(" ", need-len(chars))
+	}
+
+	out := make([]byte, 0, need/4*3)
+	for i := 0; i < need; i += 4 {
+		var v [4]byte
+		for j := range v {
+			value := c.values[chars[i+j]]
+			if value < 0 {
+				return nil, fmt.Errorf("invalid character %q at column %d", chars[i+j], i+j+2)
+			}
+			v[j] = byte(value)
+		}
+		out = append(out, v[0]<<2|v[1]>>4, v[1]<<4|v[2]>>2, v[2]<<6|v[3])
+	}
+	return out[:n], nil
+}
+
+// encodeCall implements the JavaScript encode functions of the codec, which take the
+// data as an ArrayBuffer, TypedArray or DataView, a filename and options.
+func (c *uuCodec) encodeCall(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	data, err := bytesFromValue(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(data)); err != nil {
+		common.Throw(rt, err)
+	}
+	filename, err := stringFromValue(call.Argument(1))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	var o UUOptions
+	if err := exportOptions(rt, call.Argument(2), &o); err != nil {
+		common.Throw(rt, err)
+	}
+	encoded, err := c.encode(data, filename, o)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(encoded)
+}
+
+// decodeCall implements the JavaScript decode functions of the codec, which return the
+// data of the block as an ArrayBuffer.
+func (c *uuCodec) decodeCall(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	encoded, err := stringFromValue(call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if err := validateInputSize(len(encoded)); err != nil {
+		common.Throw(rt, err)
+	}
+	block, err := c.decode(encoded)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return rt.ToValue(&UUResult{Mode: block.mode, Filename: block.filename, Data: rt.NewArrayBuffer(block.data)})
+}
+
+// EncodeUU converts the bytes of an ArrayBuffer, TypedArray or DataView to a uuencoded
+// block with a "begin <mode> <filename>" header, lines of at most 45 bytes and the "end"
+// trailer.
+func (TextEncoding) EncodeUU(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	return uuencodeCodec.encodeCall(call, rt)
+}
+
+// DecodeUU parses the first uuencoded block in the input and returns its mode, filename
+// and bytes. It accepts both '`' and ' ' for zero.
+func (TextEncoding) DecodeUU(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	return uuencodeCodec.decodeCall(call, rt)
+}
+
+// EncodeXX converts bytes to an xxencoded block. It has the same layout as EncodeUU
+// but uses the xxencode alphabet, which avoids punctuation.
+func (TextEncoding) EncodeXX(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	return xxencodeCodec.encodeCall(call, rt)
+}
+
+// DecodeXX parses the first xxencoded block in the input.
+func (TextEncoding) DecodeXX(call sobek.FunctionCall, rt *sobek.Runtime) sobek.Value {
+	return xxencodeCodec.decodeCall(call, rt)
+}
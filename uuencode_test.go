@@ -0,0 +1,175 @@
+package text_encoding
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestUUEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		filename string
+		opts     UUOptions
+		expected string
+	}{
+		{name: "empty", input: []byte{}, filename: "empty", expected: "begin 644 empty\n`\nend\n"},
+		{name: "short", input: []byte("Cat"), filename: "cat.txt", expected: "begin 644 cat.txt\n#0V%T\n`\nend\n"},
+		{
+			name: "full line",
+			input: func() []byte {
+				b := make([]byte, 46)
+				for i := range b {
+					b[i] = byte(i)
+				}
+				return b
+			}(),
+			filename: "bytes.bin",
+			opts:     UUOptions{Mode: "0600"},
+			expected: "begin 0600 bytes.bin\nM``$\"`P0%!@<(\"0H+#`T.#Q`1$A,4%187&!D:&QP='A\\@(2(C)\"4F)R@I*BLL\n!+0``\n`\nend\n",
+		},
+		{name: "filename with spaces", input: []byte{0}, filename: "my file", expected: "begin 644 my file\n!````\n`\nend\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := uuencodeCodec.encode(tt.input, tt.filename, tt.opts)
+			if err != nil {
+				t.Fatalf("encode() unexpected error: %v", err)
+			}
+			if encoded != tt.expected {
+				t.Errorf("encode() = %q, want %q", encoded, tt.expected)
+			}
+			block, err := uuencodeCodec.decode(encoded)
+			if err != nil {
+				t.Fatalf("decode() unexpected error: %v", err)
+			}
+			if block.filename != tt.filename || !bytes.Equal(block.data, tt.input) {
+				t.Errorf("decode() = %q, % X, want %q, % X", block.filename, block.data, tt.filename, tt.input)
+			}
+		})
+	}
+}
+
+func TestUUDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		mode        string
+		expected    []byte
+		expectError string
+	}{
+		{name: "preamble and crlf", input: "Subject: upload\r\n\r\nbegin 755 run.sh\r\n#0V%T\r\n`\r\nend\r\n", mode: "755", expected: []byte("Cat")},
+		{name: "spaces for zero", input: "begin 644 z\n\"    \n \nend\n", mode: "644", expected: []byte{0, 0}},
+		{name: "stripped trailing spaces", input: "begin 644 z\n\"\n\nend\n", mode: "644", expected: []byte{0, 0}},
+		{name: "no zero-length line", input: "begin 644 cat\n#0V%T\nend\n", mode: "644", expected: []byte("Cat")},
+		{name: "missing begin", input: "#0V%T\n`\nend\n", expectError: "missing \"begin\" line"},
+		{name: "missing end", input: "begin 644 cat\n#0V%T\n`\n", expectError: "missing \"end\" line"},
+		{name: "bad mode", input: "begin 6x4 cat\n`\nend\n", expectError: "mode must be 3 or 4 octal digits, got \"6x4\" on line 1"},
+		{name: "no filename", input: "begin 644\n`\nend\n", expectError: "malformed header on line 1"},
+		{name: "invalid character", input: "begin 644 cat\n#0V%t\n`\nend\n", expectError: "invalid character 't' at column 5 on line 2"},
+		{name: "invalid length", input: "begin 644 cat\nz0V%T\n`\nend\n", expectError: "invalid length character 'z' on line 2"},
+		{name: "data after zero-length line", input: "begin 644 cat\n`\n#0V%T\nend\n", expectError: "expected \"end\" on line 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := uuencodeCodec.decode(tt.input)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("decode() error = %v, want %q", err, tt.expectError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode() unexpected error: %v", err)
+			}
+			if block.mode != tt.mode || !bytes.Equal(block.data, tt.expected) {
+				t.Errorf("decode() = %q, % X, want %q, % X", block.mode, block.data, tt.mode, tt.expected)
+			}
+		})
+	}
+
+	if _, err := uuencodeCodec.encode([]byte("x"), "", UUOptions{}); err == nil {
+		t.Error("encode() expected error for an empty filename")
+	}
+	if _, err := uuencodeCodec.encode([]byte("x"), "a\nb", UUOptions{}); err == nil {
+		t.Error("encode() expected error for a multi-line filename")
+	}
+	if _, err := uuencodeCodec.encode([]byte("x"), "x", UUOptions{Mode: "999"}); err == nil || !strings.Contains(err.Error(), ErrInvalidOption) {
+		t.Errorf("encode() error = %v, want %q", err, ErrInvalidOption)
+	}
+}
+
+func TestXXEncode(t *testing.T) {
+	encoded, err := xxencodeCodec.encode([]byte("Cat"), "cat.txt", UUOptions{})
+	if err != nil {
+		t.Fatalf("encode() unexpected error: %v", err)
+	}
+	expected := "begin 644 cat.txt\n1Eq3o\n+\nend\n"
+	if encoded != expected {
+		t.Errorf("encode() = %q, want %q", encoded, expected)
+	}
+
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	encoded, err = xxencodeCodec.encode(data, "data.bin", UUOptions{})
+	if err != nil {
+		t.Fatalf("encode() unexpected error: %v", err)
+	}
+	if strings.ContainsAny(encoded, "`!\"#$%&'") {
+		t.Errorf("encode() = %q, want only the xxencode alphabet", encoded)
+	}
+	block, err := xxencodeCodec.decode(encoded)
+	if err != nil {
+		t.Fatalf("decode() unexpected error: %v", err)
+	}
+	if !bytes.Equal(block.data, data) {
+		t.Errorf("decode() = % X, want % X", block.data, data)
+	}
+
+	// xxencode has no space alias, so short lines are an error.
+	if _, err := xxencodeCodec.decode("begin 644 cat.txt\n1Eq3\n+\nend\n"); err == nil || !strings.Contains(err.Error(), ErrInvalidXX+": line holds 3 characters, need 4") {
+		t.Errorf("decode() error = %v, want short line", err)
+	}
+	if _, err := uuencodeCodec.decode(expected); err == nil {
+		t.Error("decode() expected error for xxencoded input")
+	}
+}
+
+func TestUUEncodeJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const block = encoding.encodeUU(encoding.encodeUTF8('Cat'), 'cat.txt', { mode: '600' });
+		const { mode, filename, data } = encoding.decodeUU(block);
+		const xx = encoding.decodeXX(encoding.encodeXX(new Uint8Array([1, 2, 3]), 'x.bin'));
+		return [
+			JSON.stringify(block),
+			mode,
+			filename,
+			data instanceof ArrayBuffer,
+			encoding.decodeUTF8(data),
+			new Uint8Array(xx.data).join(','),
+		].join('|');
+	})()`).String()
+	expected := `"begin 600 cat.txt\n#0V%T\n` + "`" + `\nend\n"|600|cat.txt|true|Cat|1,2,3`
+	if result != expected {
+		t.Errorf("uuencode = %q, want %q", result, expected)
+	}
+
+	throwing := []string{
+		`encoding.decodeUU('#0V%T')`,
+		`encoding.encodeUU('Cat', 'cat.txt')`,
+		`encoding.encodeXX([1, 2, 3], 'x.bin')`,
+		`encoding.encodeUU(new Uint8Array(1))`,
+	}
+	for _, script := range throwing {
+		if _, err := rt.RunString(script); err == nil {
+			t.Errorf("%s expected to throw", script)
+		}
+	}
+}
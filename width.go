@@ -0,0 +1,193 @@
+package text_encoding
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+	eawidth "golang.org/x/text/width"
+)
+
+// Alignments for PadOptions.Align.
+const (
+	AlignLeft   = "left"
+	AlignRight  = "right"
+	AlignCenter = "center"
+)
+
+// defaultEllipsis is appended by TruncateToWidth unless TruncateOptions.Ellipsis is set.
+const defaultEllipsis = "…"
+
+// WidthOptions configures the display width of East Asian ambiguous characters, such as
+// "±", "°", "①" and Greek and Cyrillic letters, which take one column in most terminals
+// and two in CJK terminals and fonts. AmbiguousWidth is 1 (the default) or 2.
+type WidthOptions struct {
+	AmbiguousWidth int `js:"ambiguousWidth"`
+}
+
+// PadOptions configures PadToWidth. Fill is a single grapheme one column wide, a space by
+// default. Align is where the text goes, in any case: "left" (the default), "right" or "center".
+type PadOptions struct {
+	Fill           string `js:"fill"`
+	Align          string `js:"align"`
+	AmbiguousWidth int    `js:"ambiguousWidth"`
+}
+
+// TruncateOptions configures TruncateToWidth. Ellipsis marks truncated text; it is "…"
+// unless set, and may be set to "" to cut the text without a mark.
+type TruncateOptions struct {
+	Ellipsis       *string `js:"ellipsis"`
+	AmbiguousWidth int     `js:"ambiguousWidth"`
+}
+
+// cluster is one grapheme cluster and its display width.
+type cluster struct {
+	text  string
+	width int
+}
+
+// ambiguousWidth resolves an AmbiguousWidth option.
+func ambiguousWidth(n int) (int, error) {
+	switch n {
+	case 0, 1:
+		return 1, nil
+	case 2:
+		return 2, nil
+	}
+	return 0, fmt.Errorf("%s: ambiguousWidth must be 1 or 2, got %d", ErrInvalidOption, n)
+}
+
+// clusterWidth returns the display width of a grapheme cluster that uniseg measured as
+// w columns, counting East Asian ambiguous characters as ambiguous columns.
+func clusterWidth(c string, w, ambiguous int) int {
+	if w != 1 || ambiguous == 1 {
+		return w
+	}
+	r, _ := utf8.DecodeRuneInString(c)
+	if eawidth.LookupRune(r).Kind() == eawidth.EastAsianAmbiguous {
+		return ambiguous
+	}
+	return w
+}
+
+// measureClusters splits text into grapheme clusters and measures their display width.
+func measureClusters(text string, ambiguous int) []cluster {
+	var out []cluster
+	state := -1
+	for len(text) > 0 {
+		var c string
+		var w int
+		c, text, w, state = uniseg.FirstGraphemeClusterInString(text, state)
+		out = append(out, cluster{text: c, width: clusterWidth(c, w, ambiguous)})
+	}
+	return out
+}
+
+// clustersWidth returns the display width of the clusters.
+func clustersWidth(clusters []cluster) int {
+	w := 0
+	for _, c := range clusters {
+		w += c.width
+	}
+	return w
+}
+
+// widthOptions returns the ambiguous width of the first of opts, or the default if there is none.
+func widthOptions(opts []WidthOptions) (int, error) {
+	if len(opts) > 0 {
+		return ambiguousWidth(opts[0].AmbiguousWidth)
+	}
+	return 1, nil
+}
+
+// DisplayWidth returns the number of terminal columns text takes: two for East Asian
+// wide and fullwidth characters and emoji, none for combining marks, zero-width joiners
+// and control characters, and one for everything else. It complements CountUTF8Bytes
+// and CountUTF8Runes for fields whose limits are given in display cells.
+func (TextEncoding) DisplayWidth(text string, opts ...WidthOptions) (int, error) {
+	ambiguous, err := widthOptions(opts)
+	if err != nil {
+		return 0, err
+	}
+	if err := validateText(text); err != nil {
+		return 0, err
+	}
+	return clustersWidth(measureClusters(text, ambiguous)), nil
+}
+
+// PadToWidth pads text with Fill to width columns. Text that is already width columns
+// or wider is returned unchanged.
+func (TextEncoding) PadToWidth(text string, width int, opts ...PadOptions) (string, error) {
+	var o PadOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	ambiguous, err := ambiguousWidth(o.AmbiguousWidth)
+	if err != nil {
+		return "", err
+	}
+	fill := o.Fill
+	if fill == "" {
+		fill = " "
+	}
+	if fc := measureClusters(fill, ambiguous); len(fc) != 1 || fc[0].width != 1 {
+		return "", fmt.Errorf("%s: fill must be a single character one column wide, got %q", ErrInvalidOption, fill)
+	}
+	if err := validateText(text); err != nil {
+		return "", err
+	}
+	padding := max(width-clustersWidth(measureClusters(text, ambiguous)), 0)
+	switch strings.ToLower(o.Align) {
+	case "", AlignLeft:
+		return text + strings.Repeat(fill, padding), nil
+	case AlignRight:
+		return strings.Repeat(fill, padding) + text, nil
+	case AlignCenter:
+		return strings.Repeat(fill, padding/2) + text + strings.Repeat(fill, padding-padding/2), nil
+	}
+	return "", fmt.Errorf("%s: align must be %q, %q or %q, got %q", ErrInvalidOption, AlignLeft, AlignRight, AlignCenter, o.Align)
+}
+
+// TruncateToWidth shortens text to at most width columns, replacing the end with Ellipsis.
+// It cuts between grapheme clusters, so the result can be a column narrower than width
+// when a wide character does not fit. Text that fits is returned unchanged.
+func (TextEncoding) TruncateToWidth(text string, width int, opts ...TruncateOptions) (string, error) {
+	var o TruncateOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	ambiguous, err := ambiguousWidth(o.AmbiguousWidth)
+	if err != nil {
+		return "", err
+	}
+	ellipsis := defaultEllipsis
+	if o.Ellipsis != nil {
+		ellipsis = *o.Ellipsis
+	}
+	if err := validateUTF8String(ellipsis); err != nil {
+		return "", err
+	}
+	ellipsisWidth := clustersWidth(measureClusters(ellipsis, ambiguous))
+	if width < ellipsisWidth {
+		return "", fmt.Errorf("%s: width %d is narrower than the ellipsis %q", ErrInvalidOption, width, ellipsis)
+	}
+	if err := validateText(text); err != nil {
+		return "", err
+	}
+	clusters := measureClusters(text, ambiguous)
+	if clustersWidth(clusters) <= width {
+		return text, nil
+	}
+	var b strings.Builder
+	used := 0
+	for _, c := range clusters {
+		if used+c.width > width-ellipsisWidth {
+			break
+		}
+		b.WriteString(c.text)
+		used += c.width
+	}
+	b.WriteString(ellipsis)
+	return b.String(), nil
+}
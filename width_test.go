@@ -0,0 +1,193 @@
+package text_encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name      string
+		input     string
+		ambiguous int
+		expected  int
+	}{
+		{name: "empty", input: "", expected: 0},
+		{name: "ascii", input: "Hello", expected: 5},
+		{name: "wide", input: "你好", expected: 4},
+		{name: "fullwidth", input: "ＡＢ", expected: 4},
+		{name: "halfwidth katakana", input: "ｶﾀｶﾅ", expected: 4},
+		{name: "hangul", input: "안녕", expected: 4},
+		{name: "combining marks", input: "cafe\u0301", expected: 4},
+		{name: "family emoji", input: "👨‍👩‍👧‍👦", expected: 2},
+		{name: "flag", input: "🇩🇪", expected: 2},
+		{name: "text presentation", input: "©", expected: 1},
+		{name: "control characters", input: "a\tb\n", expected: 2},
+		{name: "zero width space", input: "a\u200Bb", expected: 2},
+		{name: "ambiguous narrow", input: "±°①", expected: 3},
+		{name: "ambiguous wide", input: "±°①", ambiguous: 2, expected: 6},
+		{name: "ambiguous wide leaves wide alone", input: "你±a", ambiguous: 2, expected: 5},
+		{name: "cyrillic", input: "Привет", ambiguous: 2, expected: 12},
+		{name: "mixed", input: "Hello 🌍 你好", expected: 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, err := te.DisplayWidth(tt.input, WidthOptions{AmbiguousWidth: tt.ambiguous})
+			if err != nil {
+				t.Fatalf("DisplayWidth() unexpected error: %v", err)
+			}
+			if width != tt.expected {
+				t.Errorf("DisplayWidth(%q) = %d, want %d", tt.input, width, tt.expected)
+			}
+		})
+	}
+
+	if width, err := te.DisplayWidth("你好"); err != nil || width != 4 {
+		t.Errorf("DisplayWidth() without options = %d, %v, want 4", width, err)
+	}
+	if _, err := te.DisplayWidth("a", WidthOptions{AmbiguousWidth: 3}); err == nil || !strings.Contains(err.Error(), ErrInvalidOption) {
+		t.Errorf("DisplayWidth() error = %v, want %q", err, ErrInvalidOption)
+	}
+	if _, err := te.DisplayWidth("\xff"); err == nil || !strings.Contains(err.Error(), ErrInvalidUTF8) {
+		t.Errorf("DisplayWidth() error = %v, want %q", err, ErrInvalidUTF8)
+	}
+}
+
+func TestPadToWidth(t *testing.T) {
+	te := &TextEncoding{}
+
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		opts     PadOptions
+		expected string
+	}{
+		{name: "left", input: "abc", width: 6, expected: "abc   "},
+		{name: "right", input: "42", width: 5, opts: PadOptions{Align: AlignRight, Fill: "0"}, expected: "00042"},
+		{name: "center", input: "ab", width: 7, opts: PadOptions{Align: AlignCenter, Fill: "*"}, expected: "**ab***"},
+		{name: "align in any case", input: "42", width: 4, opts: PadOptions{Align: "Right"}, expected: "  42"},
+		{name: "wide", input: "你好", width: 6, expected: "你好  "},
+		{name: "combining marks", input: "cafe\u0301", width: 5, opts: PadOptions{Align: AlignRight}, expected: " cafe\u0301"},
+		{name: "already wide enough", input: "abcdef", width: 3, expected: "abcdef"},
+		{name: "ambiguous wide", input: "±", width: 4, opts: PadOptions{AmbiguousWidth: 2}, expected: "±  "},
+		{name: "empty", input: "", width: 2, expected: "  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.PadToWidth(tt.input, tt.width, tt.opts)
+			if err != nil {
+				t.Fatalf("PadToWidth() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("PadToWidth(%q, %d) = %q, want %q", tt.input, tt.width, result, tt.expected)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name string
+		opts PadOptions
+	}{
+		{name: "wide fill", opts: PadOptions{Fill: "你"}},
+		{name: "long fill", opts: PadOptions{Fill: "ab"}},
+		{name: "zero width fill", opts: PadOptions{Fill: "\u0301"}},
+		{name: "bad align", opts: PadOptions{Align: "middle"}},
+		{name: "bad ambiguous width", opts: PadOptions{AmbiguousWidth: -1}},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := te.PadToWidth("abc", 5, tt.opts); err == nil || !strings.Contains(err.Error(), ErrInvalidOption) {
+				t.Errorf("PadToWidth() error = %v, want %q", err, ErrInvalidOption)
+			}
+		})
+	}
+}
+
+func TestTruncateToWidth(t *testing.T) {
+	te := &TextEncoding{}
+	empty, dots := "", "..."
+
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		opts     TruncateOptions
+		expected string
+	}{
+		{name: "fits", input: "Hello", width: 5, expected: "Hello"},
+		{name: "ascii", input: "Hello, world", width: 8, expected: "Hello, …"},
+		{name: "custom ellipsis", input: "Hello, world", width: 8, opts: TruncateOptions{Ellipsis: &dots}, expected: "Hello..."},
+		{name: "no ellipsis", input: "Hello, world", width: 5, opts: TruncateOptions{Ellipsis: &empty}, expected: "Hello"},
+		{name: "wide", input: "你好世界", width: 5, expected: "你好…"},
+		{name: "wide does not fit", input: "你好世界", width: 4, expected: "你…"},
+		{name: "clusters are kept", input: "👨‍👩‍👧‍👦👨‍👩‍👧‍👦", width: 3, expected: "👨‍👩‍👧‍👦…"},
+		{name: "combining marks", input: "cafe\u0301", width: 4, expected: "cafe\u0301"},
+		{name: "combining marks cut", input: "cafe\u0301 noir", width: 5, expected: "cafe\u0301…"},
+		{name: "ambiguous wide ellipsis", input: "abcdef", width: 5, opts: TruncateOptions{AmbiguousWidth: 2}, expected: "abc…"},
+		{name: "only the ellipsis", input: "abc", width: 1, expected: "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := te.TruncateToWidth(tt.input, tt.width, tt.opts)
+			if err != nil {
+				t.Fatalf("TruncateToWidth() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("TruncateToWidth(%q, %d) = %q, want %q", tt.input, tt.width, result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := te.TruncateToWidth("abcdef", 2, TruncateOptions{Ellipsis: &dots}); err == nil || !strings.Contains(err.Error(), ErrInvalidOption) {
+		t.Errorf("TruncateToWidth() error = %v, want %q", err, ErrInvalidOption)
+	}
+	if _, err := te.TruncateToWidth("abc", 2, TruncateOptions{AmbiguousWidth: 5}); err == nil || !strings.Contains(err.Error(), ErrInvalidOption) {
+		t.Errorf("TruncateToWidth() error = %v, want %q", err, ErrInvalidOption)
+	}
+}
+
+func TestWrapAmbiguousWidth(t *testing.T) {
+	te := &TextEncoding{}
+
+	lines, err := te.Wrap("αβ γδ", 4, WidthOptions{AmbiguousWidth: 2})
+	if err != nil {
+		t.Fatalf("Wrap() unexpected error: %v", err)
+	}
+	if len(lines) != 2 || lines[0] != "αβ" || lines[1] != "γδ" {
+		t.Errorf("Wrap() = %q, want two lines", lines)
+	}
+	segments, err := te.SegmentLineBreaks("αβ γ", WidthOptions{AmbiguousWidth: 2})
+	if err != nil {
+		t.Fatalf("SegmentLineBreaks() unexpected error: %v", err)
+	}
+	if segments[0].Width != 5 {
+		t.Errorf("SegmentLineBreaks() width = %d, want 5", segments[0].Width)
+	}
+}
+
+func TestDisplayWidthJS(t *testing.T) {
+	rt := newTestRuntime(t)
+
+	result := runScript(t, rt, `(() => {
+		const name = 'カフェ・ラテ';
+		return [
+			encoding.countUTF8Runes(name),
+			encoding.displayWidth(name),
+			encoding.displayWidth('±°', { ambiguousWidth: 2 }),
+			JSON.stringify(encoding.padToWidth(name, 14)),
+			encoding.padToWidth('7', 3, { align: 'right', fill: '0' }),
+			encoding.truncateToWidth(name, 7),
+			encoding.truncateToWidth('Hello, world', 5, { ellipsis: '' }),
+		].join('|');
+	})()`).String()
+	expected := `6|12|4|"カフェ・ラテ  "|007|カフェ…|Hello`
+	if result != expected {
+		t.Errorf("display width = %q, want %q", result, expected)
+	}
+}